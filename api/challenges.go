@@ -1,36 +1,22 @@
 package api
 
 import (
-	"bytes"
 	"context"
-	"encoding/json"
 	"fmt"
-	"io"
-	"net/http"
 )
 
 func (c *ApiClient) GetChallenges(ctx context.Context) ([]ListChallenge, error) {
-	u := fmt.Sprintf("%s%s", c.baseUrl, challengesApiURL)
+	u := c.urlFor(challengesApiURL)
 	resp, err := c.get(ctx, u)
 	if err != nil {
 		return nil, err
 	}
 
-	defer resp.Body.Close()
-	var challenges ApiResponse[[]ListChallenge]
-	if err := json.NewDecoder(resp.Body).Decode(&challenges); err != nil {
-		return nil, err
-	}
-
-	if challenges.Success != true {
-		return nil, ErrFailedFetchingChals
-	}
-
-	return challenges.Data, nil
+	return decodeResponse[[]ListChallenge](resp, ErrFailedFetchingChals)
 }
 
 func (c *ApiClient) GetChallenge(ctx context.Context, id uint16) (*Challenge, error) {
-	u := fmt.Sprintf("%s%s/%d", c.baseUrl, challengesApiURL, id)
+	u := c.urlFor(fmt.Sprintf("%s/%d", challengesApiURL, id))
 
 	resp, err := c.get(ctx, u)
 
@@ -38,70 +24,31 @@ func (c *ApiClient) GetChallenge(ctx context.Context, id uint16) (*Challenge, er
 		return nil, err
 	}
 
-	defer resp.Body.Close()
-	var challenge ApiResponse[Challenge]
-	if err := json.NewDecoder(resp.Body).Decode(&challenge); err != nil {
+	challenge, err := decodeResponse[Challenge](resp, fmt.Errorf("%s: %d", errFailedFetchingChallenge, id))
+	if err != nil {
 		return nil, err
 	}
 
-	if challenge.Success != true {
-		return nil, fmt.Errorf("%s: %d", errFailedFetchingChallenge, id)
-	}
+	challenge.Connections = ParseConnectionInfo(challenge.ConnectionInfo)
 
-	return &challenge.Data, nil
+	return &challenge, nil
 }
 
 func (c *ApiClient) SubmitFlag(ctx context.Context, id int, attempt string) (*AttemptResult, error) {
-	resp, err := c.get(ctx, fmt.Sprintf("%s%s", c.baseUrl, challengesURL))
-
-	if err != nil {
-		return nil, err
-	}
-
-	bodyBytes, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("%s: %w", errFailedToReadResponseBody, err)
-	}
-	bodyString := string(bodyBytes)
-
-	nonce, err := extractCSRFToken(bodyString)
-	if err != nil {
-		return nil, err
-	}
-
-	u := fmt.Sprintf("%s%s", c.baseUrl, flagAttemptApiURL)
-
 	request := AttemptRequest{
 		ChallengeId: id,
 		Submission:  attempt,
 	}
 
-	jsonData, err := json.Marshal(request)
-	if err != nil {
-		return nil, err
-	}
-
-	req, err := http.NewRequestWithContext(ctx, "POST", u, bytes.NewBuffer(jsonData))
+	resp, err := c.sendJSON(ctx, "POST", c.urlFor(flagAttemptApiURL), request)
 	if err != nil {
 		return nil, err
 	}
 
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set(csrfTokenHeaderName, nonce)
-
-	resp, err = c.client.Do(req)
+	result, err := decodeResponse[AttemptResult](resp, fmt.Errorf("%s: %d", errFailedSubmittingFlag, id))
 	if err != nil {
 		return nil, err
 	}
-	defer resp.Body.Close()
-	var response ApiResponse[AttemptResult]
-	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
-		return nil, err
-	}
-
-	if response.Success != true {
-		return nil, fmt.Errorf("%s: %d", errFailedSubmittingFlag, id)
-	}
 
-	return &response.Data, nil
+	return &result, nil
 }
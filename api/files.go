@@ -0,0 +1,47 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"path"
+	"strings"
+)
+
+// FileName returns the name of a challenge file from its download link.
+func FileName(fileURL string) string {
+	cleanPath := strings.Split(fileURL, "?")[0]
+	return path.Base(cleanPath)
+}
+
+func (c *ApiClient) DownloadFile(ctx context.Context, fileURL string) ([]byte, error) {
+	u, err := c.resolveURL(fileURL)
+	if err != nil {
+		return nil, fmt.Errorf("%s %s: %w", errFailedDownloadingFile, FileName(fileURL), err)
+	}
+
+	resp, err := c.get(ctx, u)
+	if err != nil {
+		return nil, err
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, maxSnippetLength))
+		return nil, &APIError{
+			Err:        fmt.Errorf("%s %s", errFailedDownloadingFile, FileName(fileURL)),
+			StatusCode: resp.StatusCode,
+			Endpoint:   describeRequest(resp),
+			Snippet:    snippet(body),
+		}
+	}
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", errFailedToReadResponseBody, err)
+	}
+
+	return data, nil
+}
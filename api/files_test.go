@@ -0,0 +1,72 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestFileName(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"/files/abcdef/chall.zip?token=xyz", "chall.zip"},
+		{"https://cdn.example.com/files/abcdef/chall.tar.gz", "chall.tar.gz"},
+		{"/files/abcdef/binary", "binary"},
+	}
+
+	for _, test := range tests {
+		if name := FileName(test.input); name != test.expected {
+			t.Errorf("FileName(%q) = %q, want %q", test.input, name, test.expected)
+		}
+	}
+}
+
+func TestDownloadFile_Success(t *testing.T) {
+	var requested string
+	mock := &mockClient{
+		t: t,
+		doFunc: func(req *http.Request) (*http.Response, error) {
+			requested = req.URL.String()
+			return newResponse(200, "file contents"), nil
+		},
+	}
+
+	base, _ := url.Parse("https://ctf.example.com")
+	api := &ApiClient{
+		client:  mock,
+		baseUrl: base,
+	}
+
+	data, err := api.DownloadFile(context.Background(), "/files/abc/chall.zip?token=xyz")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if string(data) != "file contents" {
+		t.Errorf("expected file contents, got %q", string(data))
+	}
+	if requested != "https://ctf.example.com/files/abc/chall.zip?token=xyz" {
+		t.Errorf("unexpected request URL %q", requested)
+	}
+}
+
+func TestDownloadFile_NotFound(t *testing.T) {
+	mock := mockResponse(t, newResponse(404, "not found"))
+
+	base, _ := url.Parse("https://ctf.example.com")
+	api := &ApiClient{
+		client:  mock,
+		baseUrl: base,
+	}
+
+	_, err := api.DownloadFile(context.Background(), "/files/abc/chall.zip")
+	if err == nil {
+		t.Fatalf("expected error, got nil")
+	}
+	if !strings.Contains(err.Error(), errFailedDownloadingFile) {
+		t.Errorf("expected error to contain %q, got %q", errFailedDownloadingFile, err.Error())
+	}
+}
@@ -0,0 +1,197 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+var customFieldRegex = regexp.MustCompile(`^fields\[(\d+)\]$`)
+
+// submitForm fills in one of CTFd's HTML forms, such as /register or
+// /teams/join, the same way a browser would. CTFd redirects away from the
+// form on success and renders it again with alerts on failure, which are
+// returned wrapping failure. The URL the form redirected to is returned.
+func (c *ApiClient) submitForm(ctx context.Context, path string, values url.Values, failure error) (*url.URL, error) {
+	body, err := c.getPageBody(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+
+	captcha, err := checkCAPTCHA(body)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", errFailedToCheckCAPTCHA, err)
+	}
+	if captcha {
+		return nil, ErrCaptchaRequired
+	}
+
+	nonce, err := extractNonce(body)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", errFailedToExtractNonce, err)
+	}
+	values.Set("nonce", nonce)
+
+	resp, err := c.postForm(ctx, c.urlFor(path), values)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	bodyBytes, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", errFailedToReadResponseBody, err)
+	}
+
+	final := resp.Request.URL
+	if final.Path == c.baseUrl.Path+path {
+		alerts := extractAlerts(string(bodyBytes), "alert-danger")
+		if len(alerts) == 0 {
+			alerts = []string{resp.Status}
+		}
+		return nil, fmt.Errorf("%w: %s", failure, strings.Join(alerts, ", "))
+	}
+
+	return final, nil
+}
+
+// extractAlerts returns the messages of the alerts with the given class on a
+// CTFd page, e.g. "alert-danger" for errors.
+func extractAlerts(htmlBody, class string) []string {
+	doc, err := html.Parse(strings.NewReader(htmlBody))
+	if err != nil {
+		return nil
+	}
+
+	var alerts []string
+	var f func(*html.Node)
+	f = func(n *html.Node) {
+		if n.Type == html.ElementNode && hasClass(n, class) {
+			if text := alertText(n); text != "" {
+				alerts = append(alerts, text)
+			}
+			return
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			f(c)
+		}
+	}
+
+	f(doc)
+	return alerts
+}
+
+func alertText(n *html.Node) string {
+	var parts []string
+	var f func(*html.Node)
+	f = func(n *html.Node) {
+		if n.Type == html.ElementNode && (n.Data == "button" || hasClass(n, "sr-only")) {
+			return
+		}
+		if n.Type == html.TextNode {
+			if text := strings.TrimSpace(n.Data); text != "" {
+				parts = append(parts, text)
+			}
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			f(c)
+		}
+	}
+
+	f(n)
+	return strings.Join(parts, " ")
+}
+
+func hasClass(n *html.Node, class string) bool {
+	for _, attr := range n.Attr {
+		if attr.Key == "class" {
+			for _, c := range strings.Fields(attr.Val) {
+				if c == class {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+func attr(n *html.Node, key string) string {
+	for _, a := range n.Attr {
+		if a.Key == key {
+			return a.Val
+		}
+	}
+	return ""
+}
+
+// extractFormFields finds the custom fields ("fields[N]") of a CTFd form and
+// whether it asks for a registration code.
+func extractFormFields(htmlBody string) ([]FormField, bool, error) {
+	doc, err := html.Parse(strings.NewReader(htmlBody))
+	if err != nil {
+		return nil, false, err
+	}
+
+	labels := map[string]string{}
+	var fields []FormField
+	hasCode := false
+
+	var f func(*html.Node)
+	f = func(n *html.Node) {
+		if n.Type == html.ElementNode {
+			switch n.Data {
+			case "label":
+				var text []string
+				for c := n.FirstChild; c != nil; c = c.NextSibling {
+					if c.Type == html.TextNode && strings.TrimSpace(c.Data) != "" {
+						text = append(text, strings.TrimSpace(c.Data))
+					}
+				}
+				labels[attr(n, "for")] = strings.Join(text, " ")
+			case "input", "select", "textarea":
+				name := attr(n, "name")
+				if name == "registration_code" {
+					hasCode = true
+				}
+				if m := customFieldRegex.FindStringSubmatch(name); m != nil {
+					id, _ := strconv.Atoi(m[1])
+					_, required := attrValue(n, "required")
+					fieldType := attr(n, "type")
+					if n.Data != "input" {
+						fieldType = n.Data
+					}
+					fields = append(fields, FormField{Id: id, Type: fieldType, Required: required})
+				}
+			}
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			f(c)
+		}
+	}
+
+	f(doc)
+
+	for i := range fields {
+		fields[i].Name = labels[fmt.Sprintf("fields[%d]", fields[i].Id)]
+		if fields[i].Name == "" {
+			fields[i].Name = fmt.Sprintf("Field %d", fields[i].Id)
+		}
+	}
+
+	return fields, hasCode, nil
+}
+
+func attrValue(n *html.Node, key string) (string, bool) {
+	for _, a := range n.Attr {
+		if a.Key == key {
+			return a.Val, true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,22 @@
+package api
+
+import (
+	"context"
+	"fmt"
+)
+
+func (c *ApiClient) GetHint(ctx context.Context, id int) (*Hint, error) {
+	u := c.urlFor(fmt.Sprintf("%s/%d", hintsApiURL, id))
+
+	resp, err := c.get(ctx, u)
+	if err != nil {
+		return nil, err
+	}
+
+	hint, err := decodeResponse[Hint](resp, fmt.Errorf("%s: %d", errFailedFetchingHint, id))
+	if err != nil {
+		return nil, err
+	}
+
+	return &hint, nil
+}
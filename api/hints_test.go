@@ -0,0 +1,58 @@
+package api
+
+import (
+	"context"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestGetHint_Success(t *testing.T) {
+	responseBody := `{
+		"success": true,
+		"data": {
+			"id": 3,
+			"type": "standard",
+			"challenge": 42,
+			"content": "Look closer",
+			"cost": 0
+		}
+	}`
+
+	mock := mockResponse(t, newResponse(200, responseBody))
+
+	base, _ := url.Parse("https://ctf.example.com")
+	api := &ApiClient{
+		client:  mock,
+		baseUrl: base,
+	}
+
+	hint, err := api.GetHint(context.Background(), 3)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if hint.Content != "Look closer" {
+		t.Errorf("expected hint content 'Look closer', got %q", hint.Content)
+	}
+	if hint.Challenge != 42 {
+		t.Errorf("expected challenge 42, got %d", hint.Challenge)
+	}
+}
+
+func TestGetHint_Failure(t *testing.T) {
+	mock := mockResponse(t, newResponse(200, `{"success": false, "data": {}}`))
+
+	base, _ := url.Parse("https://ctf.example.com")
+	api := &ApiClient{
+		client:  mock,
+		baseUrl: base,
+	}
+
+	_, err := api.GetHint(context.Background(), 3)
+	if err == nil {
+		t.Fatalf("expected error, got nil")
+	}
+	if !strings.Contains(err.Error(), errFailedFetchingHint) {
+		t.Errorf("expected error to contain %q, got %q", errFailedFetchingHint, err.Error())
+	}
+}
@@ -18,7 +18,7 @@ func (c *ApiClient) Login(ctx context.Context, name string, password string) err
 		return ErrInvalidPassword
 	}
 
-	bodyString, err := c.getLoginPageBody(ctx)
+	bodyString, err := c.getPageBody(ctx, loginURL)
 	if err != nil {
 		return fmt.Errorf("%s: %w", errFailedToGetLoginPage, err)
 	}
@@ -45,8 +45,8 @@ func (c *ApiClient) Login(ctx context.Context, name string, password string) err
 	return nil
 }
 
-func (c *ApiClient) getLoginPageBody(ctx context.Context) (string, error) {
-	u := fmt.Sprintf("%s%s", c.baseUrl, loginURL)
+func (c *ApiClient) getPageBody(ctx context.Context, path string) (string, error) {
+	u := c.urlFor(path)
 
 	resp, err := c.get(ctx, u)
 
@@ -90,7 +90,7 @@ func (c *ApiClient) performLogin(ctx context.Context, username string, password
 		"nonce":    {nonce},
 	}
 
-	resp, err := c.postForm(ctx, fmt.Sprintf("%s%s", c.baseUrl, loginURL), body)
+	resp, err := c.postForm(ctx, c.urlFor(loginURL), body)
 
 	if err != nil {
 		if errors.Is(err, context.Canceled) {
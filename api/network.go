@@ -0,0 +1,115 @@
+package api
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+)
+
+type NetworkOptions struct {
+	// Proxy is an http, https or socks5 proxy URL. When empty the
+	// HTTP_PROXY, HTTPS_PROXY and NO_PROXY environment variables are used.
+	Proxy string `json:"proxy,omitempty"`
+	// CACert is a PEM bundle trusted in addition to the system roots.
+	CACert string `json:"ca_cert,omitempty"`
+	// Insecure disables TLS certificate verification.
+	Insecure bool `json:"insecure,omitempty"`
+	// ClientCert and ClientKey are PEM files used for mutual TLS.
+	ClientCert string `json:"client_cert,omitempty"`
+	ClientKey  string `json:"client_key,omitempty"`
+	// BasicAuthUser and BasicAuthPassword are sent to gateways in front of
+	// the CTFd instance.
+	BasicAuthUser     string            `json:"basic_auth_user,omitempty"`
+	BasicAuthPassword string            `json:"basic_auth_password,omitempty"`
+	Headers           map[string]string `json:"headers,omitempty"`
+	UserAgent         string            `json:"user_agent,omitempty"`
+}
+
+// newTransport creates the transport for an instance on host. The headers and
+// basic auth credentials of opts are only sent to host.
+func newTransport(opts NetworkOptions, host string) (http.RoundTripper, error) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+
+	if opts.Proxy != "" {
+		proxy, err := url.Parse(opts.Proxy)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", errInvalidProxy, err)
+		}
+		switch proxy.Scheme {
+		case "http", "https", "socks5", "socks5h":
+		default:
+			return nil, fmt.Errorf("%s: unsupported scheme '%s'", errInvalidProxy, proxy.Scheme)
+		}
+		transport.Proxy = http.ProxyURL(proxy)
+	}
+
+	tlsConfig := &tls.Config{InsecureSkipVerify: opts.Insecure}
+
+	if opts.CACert != "" {
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		pem, err := os.ReadFile(opts.CACert)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", errFailedToLoadCACert, err)
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("%s: no certificates found in %s", errFailedToLoadCACert, opts.CACert)
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	if opts.ClientCert != "" || opts.ClientKey != "" {
+		cert, err := tls.LoadX509KeyPair(opts.ClientCert, opts.ClientKey)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", errFailedToLoadClientCert, err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	transport.TLSClientConfig = tlsConfig
+
+	if opts.BasicAuthUser == "" && len(opts.Headers) == 0 && opts.UserAgent == "" {
+		return transport, nil
+	}
+
+	return &headerTransport{base: transport, opts: opts, host: host}, nil
+}
+
+// headerTransport adds the configured headers to every request that doesn't
+// already set them. The headers and basic auth credentials, which often
+// carry secrets for a reverse proxy, are only added to requests to host, not
+// to redirects elsewhere or files on other hosts.
+type headerTransport struct {
+	base http.RoundTripper
+	opts NetworkOptions
+	host string
+}
+
+func (t *headerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+
+	if t.opts.UserAgent != "" && req.Header.Get("User-Agent") == "" {
+		req.Header.Set("User-Agent", t.opts.UserAgent)
+	}
+
+	if req.URL.Host != t.host {
+		return t.base.RoundTrip(req)
+	}
+
+	for k, v := range t.opts.Headers {
+		if req.Header.Get(k) == "" {
+			req.Header.Set(k, v)
+		}
+	}
+
+	if t.opts.BasicAuthUser != "" && req.Header.Get("Authorization") == "" {
+		req.SetBasicAuth(t.opts.BasicAuthUser, t.opts.BasicAuthPassword)
+	}
+
+	return t.base.RoundTrip(req)
+}
@@ -0,0 +1,138 @@
+package api
+
+import (
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestNewTransport_Proxy(t *testing.T) {
+	transport, err := newTransport(NetworkOptions{Proxy: "socks5://127.0.0.1:1080"}, "ctf.example.com")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	req, _ := http.NewRequest("GET", "https://ctf.example.com/", nil)
+	proxy, err := transport.(*http.Transport).Proxy(req)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if proxy.String() != "socks5://127.0.0.1:1080" {
+		t.Errorf("expected socks5 proxy, got %v", proxy)
+	}
+}
+
+func TestNewTransport_InvalidOptions(t *testing.T) {
+	missing := filepath.Join(t.TempDir(), "missing.pem")
+
+	empty := filepath.Join(t.TempDir(), "empty.pem")
+	if err := os.WriteFile(empty, []byte("not a certificate"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		opts     NetworkOptions
+		errorMsg string
+	}{
+		{"Unsupported proxy scheme", NetworkOptions{Proxy: "ftp://127.0.0.1"}, errInvalidProxy},
+		{"Missing CA bundle", NetworkOptions{CACert: missing}, errFailedToLoadCACert},
+		{"CA bundle without certificates", NetworkOptions{CACert: empty}, errFailedToLoadCACert},
+		{"Missing client certificate", NetworkOptions{ClientCert: missing, ClientKey: missing}, errFailedToLoadClientCert},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := newTransport(tt.opts, "ctf.example.com")
+			if err == nil {
+				t.Fatalf("expected error, got nil")
+			}
+			if !strings.Contains(err.Error(), tt.errorMsg) {
+				t.Errorf("expected error containing %q, got %q", tt.errorMsg, err.Error())
+			}
+		})
+	}
+}
+
+func TestNewTransport_Insecure(t *testing.T) {
+	transport, err := newTransport(NetworkOptions{Insecure: true}, "ctf.example.com")
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if !transport.(*http.Transport).TLSClientConfig.InsecureSkipVerify {
+		t.Errorf("expected certificate verification to be disabled")
+	}
+}
+
+func TestHeaderTransport(t *testing.T) {
+	var got *http.Request
+	transport := &headerTransport{
+		base: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+			got = req
+			return newResponse(200, ""), nil
+		}),
+		opts: NetworkOptions{
+			BasicAuthUser:     "gate",
+			BasicAuthPassword: "keeper",
+			Headers:           map[string]string{"X-Team": "hackers", "Content-Type": "text/plain"},
+			UserAgent:         "ctfd-cli-test",
+		},
+		host: "ctf.example.com",
+	}
+
+	req, _ := http.NewRequest("POST", "https://ctf.example.com/login", strings.NewReader(url.Values{}.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	if _, err := transport.RoundTrip(req); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if got.Header.Get("X-Team") != "hackers" {
+		t.Errorf("expected extra header to be set, got %q", got.Header.Get("X-Team"))
+	}
+	if got.Header.Get("Content-Type") != "application/x-www-form-urlencoded" {
+		t.Errorf("expected request header to take precedence, got %q", got.Header.Get("Content-Type"))
+	}
+	if got.Header.Get("User-Agent") != "ctfd-cli-test" {
+		t.Errorf("expected user agent to be set, got %q", got.Header.Get("User-Agent"))
+	}
+	if user, password, ok := got.BasicAuth(); !ok || user != "gate" || password != "keeper" {
+		t.Errorf("expected basic auth gate:keeper, got %q:%q", user, password)
+	}
+	if req.Header.Get("X-Team") != "" {
+		t.Errorf("expected original request to be left unchanged")
+	}
+}
+
+func TestHeaderTransport_OtherHost(t *testing.T) {
+	var got *http.Request
+	transport := &headerTransport{
+		base: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+			got = req
+			return newResponse(200, ""), nil
+		}),
+		opts: NetworkOptions{
+			BasicAuthUser: "gate",
+			Headers:       map[string]string{"X-Proxy-Secret": "hunter2"},
+			UserAgent:     "ctfd-cli-test",
+		},
+		host: "ctf.example.com",
+	}
+
+	req, _ := http.NewRequest("GET", "https://files.example.com/chall.zip", nil)
+	if _, err := transport.RoundTrip(req); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if got.Header.Get("X-Proxy-Secret") != "" {
+		t.Errorf("expected no extra headers for another host")
+	}
+	if _, _, ok := got.BasicAuth(); ok {
+		t.Errorf("expected no basic auth for another host")
+	}
+	if got.Header.Get("User-Agent") != "ctfd-cli-test" {
+		t.Errorf("expected user agent to be set, got %q", got.Header.Get("User-Agent"))
+	}
+}
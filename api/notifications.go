@@ -0,0 +1,16 @@
+package api
+
+import (
+	"context"
+	"errors"
+)
+
+// GetNotifications returns the notifications sent by the organizers.
+func (c *ApiClient) GetNotifications(ctx context.Context) ([]Notification, error) {
+	resp, err := c.get(ctx, c.urlFor(notificationsApiURL))
+	if err != nil {
+		return nil, err
+	}
+
+	return decodeResponse[[]Notification](resp, errors.New(errFailedFetchingNotifications))
+}
@@ -0,0 +1,53 @@
+package api
+
+import (
+	"context"
+	"net/url"
+	"testing"
+)
+
+func TestGetNotifications_Success(t *testing.T) {
+	responseBody := `{
+		"success": true,
+		"data": [
+			{
+				"id": 3,
+				"title": "Hint released",
+				"content": "Check the new hint for **pwn100**",
+				"html": "<p>Check the new hint for <strong>pwn100</strong></p>",
+				"date": "2024-05-01T12:00:00+00:00",
+				"user_id": null,
+				"team_id": null
+			}
+		]
+	}`
+
+	mock := mockResponse(t, newResponse(200, responseBody))
+
+	base, _ := url.Parse("https://ctf.example.com")
+	api := &ApiClient{client: mock, baseUrl: base}
+
+	notifications, err := api.GetNotifications(context.Background())
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if len(notifications) != 1 {
+		t.Fatalf("expected 1 notification, got %d", len(notifications))
+	}
+	n := notifications[0]
+	if n.Id != 3 || n.Title != "Hint released" || n.Content != "Check the new hint for **pwn100**" {
+		t.Errorf("unexpected notification %+v", n)
+	}
+}
+
+func TestGetNotifications_Failure(t *testing.T) {
+	mock := mockResponse(t, newResponse(200, `{"success": false}`))
+
+	base, _ := url.Parse("https://ctf.example.com")
+	api := &ApiClient{client: mock, baseUrl: base}
+
+	if _, err := api.GetNotifications(context.Background()); err == nil {
+		t.Fatal("expected an error")
+	}
+}
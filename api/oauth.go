@@ -0,0 +1,178 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+)
+
+type noRedirectKey struct{}
+
+// withoutRedirects makes the client return redirects instead of following
+// them, to read the Location CTFd sends.
+func withoutRedirects(ctx context.Context) context.Context {
+	return context.WithValue(ctx, noRedirectKey{}, true)
+}
+
+func checkRedirect(req *http.Request, via []*http.Request) error {
+	if req.Context().Value(noRedirectKey{}) != nil {
+		return http.ErrUseLastResponse
+	}
+	if len(via) >= 10 {
+		return errors.New("stopped after 10 redirects")
+	}
+	return nil
+}
+
+// StartOAuth begins an OAuth login, e.g. with MajorLeagueCyber, and returns
+// the authorization URL to open in a browser. The state CTFd checks on the
+// way back is tied to the client's session, so the login has to be finished
+// with CompleteOAuth on the same client.
+func (c *ApiClient) StartOAuth(ctx context.Context) (string, error) {
+	resp, err := c.get(withoutRedirects(ctx), c.urlFor(oauthURL))
+	if err != nil {
+		return "", err
+	}
+	resp.Body.Close()
+
+	location, err := resp.Location()
+	if err != nil {
+		return "", fmt.Errorf("%w: %s", ErrOAuthNotConfigured, resp.Status)
+	}
+
+	// CTFd redirects back to its login page when OAuth isn't set up.
+	if location.Host == c.baseUrl.Host {
+		return "", ErrOAuthNotConfigured
+	}
+
+	return location.String(), nil
+}
+
+// CompleteOAuth finishes an OAuth login with the URL the provider redirected
+// the browser to. Only the code and state are used, so both CTFd's own
+// /redirect URL and the URL of a local callback work.
+func (c *ApiClient) CompleteOAuth(ctx context.Context, redirectURL string) error {
+	u, err := url.Parse(redirectURL)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrOAuthFailed, err)
+	}
+
+	query := u.Query()
+	if e := query.Get("error"); e != "" {
+		if description := query.Get("error_description"); description != "" {
+			e = description
+		}
+		return fmt.Errorf("%w: %s", ErrOAuthFailed, e)
+	}
+	if query.Get("code") == "" || query.Get("state") == "" {
+		return fmt.Errorf("%w: %s", ErrOAuthFailed, errMissingOAuthCode)
+	}
+
+	callback := url.Values{
+		"code":  {query.Get("code")},
+		"state": {query.Get("state")},
+	}
+
+	resp, err := c.get(ctx, c.urlFor(oauthRedirectURL)+"?"+callback.Encode())
+	if errors.Is(err, ErrSessionExpired) {
+		return ErrOAuthFailed
+	}
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		return fmt.Errorf("%w: %s", ErrOAuthFailed, resp.Status)
+	}
+
+	return nil
+}
+
+// OAuthCallback is a local HTTP server receiving the browser after the
+// provider authorized the login. Providers only redirect to it when they
+// accept loopback redirect URIs, which CTFd's MajorLeagueCyber doesn't, so it
+// is only used when asked for.
+type OAuthCallback struct {
+	listener net.Listener
+	server   *http.Server
+	result   chan string
+	done     chan struct{}
+}
+
+// ListenOAuthCallback starts a callback server on addr, such as
+// "127.0.0.1:0" for a random port.
+func ListenOAuthCallback(addr string) (*OAuthCallback, error) {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+
+	cb := &OAuthCallback{
+		listener: listener,
+		result:   make(chan string, 1),
+		done:     make(chan struct{}),
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc(oauthCallbackPath, cb.handle)
+	cb.server = &http.Server{Handler: mux}
+
+	go cb.server.Serve(listener)
+	return cb, nil
+}
+
+func (cb *OAuthCallback) handle(w http.ResponseWriter, r *http.Request) {
+	select {
+	case cb.result <- cb.URL() + "?" + r.URL.RawQuery:
+	default:
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	fmt.Fprint(w, "<html><body><p>Login received, you can close this window and return to ctfd-cli.</p></body></html>")
+}
+
+// URL is the redirect URI of the callback.
+func (cb *OAuthCallback) URL() string {
+	return "http://" + cb.listener.Addr().String() + oauthCallbackPath
+}
+
+// AuthorizationURL points the redirect URI of an authorization URL at the
+// callback.
+func (cb *OAuthCallback) AuthorizationURL(authURL string) (string, error) {
+	u, err := url.Parse(authURL)
+	if err != nil {
+		return "", err
+	}
+
+	query := u.Query()
+	query.Set("redirect_uri", cb.URL())
+	u.RawQuery = query.Encode()
+	return u.String(), nil
+}
+
+// Wait returns the URL the browser was redirected to, to be passed to
+// CompleteOAuth.
+func (cb *OAuthCallback) Wait(ctx context.Context) (string, error) {
+	select {
+	case u := <-cb.result:
+		return u, nil
+	case <-cb.done:
+		return "", ErrOAuthCallbackClosed
+	case <-ctx.Done():
+		return "", ctx.Err()
+	}
+}
+
+func (cb *OAuthCallback) Close() error {
+	select {
+	case <-cb.done:
+		return nil
+	default:
+		close(cb.done)
+	}
+	return cb.server.Close()
+}
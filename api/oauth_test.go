@@ -0,0 +1,195 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/cookiejar"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+// fakeOAuth is a CTFd instance using a fake OAuth provider. Like CTFd it
+// ties the state to the session that started the login and only accepts the
+// code issued by the provider.
+type fakeOAuth struct {
+	ctfd     *httptest.Server
+	provider *httptest.Server
+}
+
+const fakeOAuthCode = "fake-code"
+
+func newFakeOAuth(t *testing.T) *fakeOAuth {
+	f := &fakeOAuth{}
+
+	f.provider = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		redirect := r.URL.Query().Get("redirect_uri")
+		if redirect == "" {
+			redirect = f.ctfd.URL + "/redirect"
+		}
+		target := fmt.Sprintf("%s?code=%s&state=%s", redirect, fakeOAuthCode, url.QueryEscape(r.URL.Query().Get("state")))
+		http.Redirect(w, r, target, http.StatusFound)
+	}))
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/oauth", func(w http.ResponseWriter, r *http.Request) {
+		http.SetCookie(w, &http.Cookie{Name: "session", Value: "nonce-123", Path: "/"})
+		http.Redirect(w, r, f.provider.URL+"/authorize?client_id=ctfd&state=nonce-123", http.StatusFound)
+	})
+	mux.HandleFunc("/redirect", func(w http.ResponseWriter, r *http.Request) {
+		session, err := r.Cookie("session")
+		if err != nil || session.Value != r.URL.Query().Get("state") {
+			http.Error(w, "Forbidden", http.StatusForbidden)
+			return
+		}
+		if r.URL.Query().Get("code") != fakeOAuthCode {
+			http.Redirect(w, r, "/login", http.StatusFound)
+			return
+		}
+		http.SetCookie(w, &http.Cookie{Name: "session", Value: "logged-in", Path: "/"})
+		http.Redirect(w, r, "/challenges", http.StatusFound)
+	})
+	mux.HandleFunc("/challenges", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "<html><head><title>Challenges</title></head></html>")
+	})
+	mux.HandleFunc("/login", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "<html><head><title>Login</title></head></html>")
+	})
+	mux.HandleFunc("/api/v1/users/me", func(w http.ResponseWriter, r *http.Request) {
+		if session, err := r.Cookie("session"); err != nil || session.Value != "logged-in" {
+			http.Redirect(w, r, "/login", http.StatusFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"success": true, "data": {"id": 1, "name": "mlc-player"}}`)
+	})
+	f.ctfd = httptest.NewServer(mux)
+
+	t.Cleanup(func() {
+		f.ctfd.Close()
+		f.provider.Close()
+	})
+	return f
+}
+
+// browser follows redirects with its own cookies, like the user's browser.
+func browser(t *testing.T, u string) *http.Response {
+	jar, _ := cookiejar.New(nil)
+	resp, err := (&http.Client{Jar: jar}).Get(u)
+	if err != nil {
+		t.Fatalf("browser request failed: %v", err)
+	}
+	resp.Body.Close()
+	return resp
+}
+
+func TestOAuth_LocalCallback(t *testing.T) {
+	f := newFakeOAuth(t)
+
+	client, err := NewApiClient(f.ctfd.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	authURL, err := client.StartOAuth(context.Background())
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	callback, err := ListenOAuthCallback("127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer callback.Close()
+
+	authURL, err = callback.AuthorizationURL(authURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	browser(t, authURL)
+
+	redirected, err := callback.Wait(context.Background())
+	if err != nil {
+		t.Fatalf("expected callback, got %v", err)
+	}
+
+	if err := client.CompleteOAuth(context.Background(), redirected); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	user, err := client.GetMe(context.Background())
+	if err != nil {
+		t.Fatalf("expected to be logged in, got %v", err)
+	}
+	if user.Name != "mlc-player" {
+		t.Errorf("unexpected user %+v", user)
+	}
+}
+
+func TestOAuth_PastedRedirect(t *testing.T) {
+	f := newFakeOAuth(t)
+
+	client, err := NewApiClient(f.ctfd.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	authURL, err := client.StartOAuth(context.Background())
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	// The browser ends up on CTFd's /redirect without the client's session
+	// and is rejected, the user copies the URL from the address bar.
+	resp := browser(t, authURL)
+	if resp.StatusCode != http.StatusForbidden {
+		t.Fatalf("expected browser to be rejected, got %s", resp.Status)
+	}
+
+	if err := client.CompleteOAuth(context.Background(), resp.Request.URL.String()); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if _, err := client.GetMe(context.Background()); err != nil {
+		t.Fatalf("expected to be logged in, got %v", err)
+	}
+}
+
+func TestOAuth_InvalidCode(t *testing.T) {
+	f := newFakeOAuth(t)
+
+	client, err := NewApiClient(f.ctfd.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.StartOAuth(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	err = client.CompleteOAuth(context.Background(), f.ctfd.URL+"/redirect?code=wrong&state=nonce-123")
+	if !errors.Is(err, ErrOAuthFailed) {
+		t.Errorf("expected %v, got %v", ErrOAuthFailed, err)
+	}
+}
+
+func TestCompleteOAuth_ProviderError(t *testing.T) {
+	api := &ApiClient{client: &mockClient{t: t}}
+
+	err := api.CompleteOAuth(context.Background(), "http://127.0.0.1/callback?error=access_denied&error_description=User+denied+access")
+	if !errors.Is(err, ErrOAuthFailed) || err.Error() != "OAuth login failed: User denied access" {
+		t.Errorf("unexpected error %v", err)
+	}
+}
+
+func TestStartOAuth_NotConfigured(t *testing.T) {
+	resp := newResponse(302, "")
+	resp.Header.Set("Location", "/login")
+
+	base, _ := url.Parse("https://ctf.example.com")
+	api := &ApiClient{client: mockResponse(t, resp), baseUrl: base}
+
+	if _, err := api.StartOAuth(context.Background()); !errors.Is(err, ErrOAuthNotConfigured) {
+		t.Errorf("expected %v, got %v", ErrOAuthNotConfigured, err)
+	}
+}
@@ -0,0 +1,74 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// FormField is a custom field an admin added to the registration or team
+// form.
+type FormField struct {
+	Id       int
+	Name     string
+	Type     string
+	Required bool
+}
+
+type RegistrationForm struct {
+	Fields []FormField
+	// CodeRequired is set when the instance requires a registration code.
+	CodeRequired bool
+}
+
+type Registration struct {
+	Name             string
+	Email            string
+	Password         string
+	RegistrationCode string
+	// Fields holds the values of custom fields by FormField.Id.
+	Fields map[int]string
+}
+
+// GetRegistrationForm reads the registration page to find the custom fields
+// and whether a registration code is needed.
+func (c *ApiClient) GetRegistrationForm(ctx context.Context) (*RegistrationForm, error) {
+	body, err := c.getPageBody(ctx, registerURL)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", errFailedToGetRegisterPage, err)
+	}
+
+	fields, codeRequired, err := extractFormFields(body)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", errFailedToGetRegisterPage, err)
+	}
+
+	return &RegistrationForm{Fields: fields, CodeRequired: codeRequired}, nil
+}
+
+// Register creates a new account. On success the client is logged in as the
+// new user.
+func (c *ApiClient) Register(ctx context.Context, r Registration) error {
+	if strings.TrimSpace(r.Name) == "" {
+		return ErrInvalidUsername
+	}
+	if strings.TrimSpace(r.Password) == "" {
+		return ErrInvalidPassword
+	}
+
+	values := url.Values{
+		"name":     {r.Name},
+		"email":    {r.Email},
+		"password": {r.Password},
+	}
+	if r.RegistrationCode != "" {
+		values.Set("registration_code", r.RegistrationCode)
+	}
+	for id, value := range r.Fields {
+		values.Set(fmt.Sprintf("fields[%d]", id), value)
+	}
+
+	_, err := c.submitForm(ctx, registerURL, values, ErrRegistrationFailed)
+	return err
+}
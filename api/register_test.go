@@ -0,0 +1,176 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+const registerPage = `
+<html>
+	<head>
+		<title>Register</title>
+	</head>
+	<body>
+		<form method="post">
+			<input id="name" name="name" type="text">
+			<input id="email" name="email" type="email">
+			<input id="password" name="password" type="password">
+			<label for="fields[1]">Country</label>
+			<input id="fields[1]" name="fields[1]" type="text" required>
+			<label for="fields[2]">
+				Newsletter
+			</label>
+			<input id="fields[2]" name="fields[2]" type="checkbox">
+			<input id="registration_code" name="registration_code" type="text">
+			<input id="nonce" name="nonce" type="hidden" value="test-nonce">
+		</form>
+	</body>
+</html>`
+
+// redirectingClient answers the form page first and then answers the POST as
+// if CTFd redirected to redirectPath.
+func redirectingClient(t *testing.T, page, redirectPath string, form *url.Values) *mockClient {
+	calls := 0
+	return &mockClient{
+		t: t,
+		doFunc: func(req *http.Request) (*http.Response, error) {
+			calls++
+			if calls == 1 {
+				resp := newResponse(200, page)
+				resp.Request = req
+				return resp, nil
+			}
+
+			body, _ := io.ReadAll(req.Body)
+			*form, _ = url.ParseQuery(string(body))
+
+			redirected := req.Clone(req.Context())
+			redirected.Method = "GET"
+			redirected.URL, _ = url.Parse("https://ctf.example.com" + redirectPath)
+			resp := newResponse(200, "<html></html>")
+			resp.Request = redirected
+			return resp, nil
+		},
+	}
+}
+
+func TestGetRegistrationForm(t *testing.T) {
+	base, _ := url.Parse("https://ctf.example.com")
+	api := &ApiClient{
+		client:  mockResponse(t, newResponse(200, registerPage)),
+		baseUrl: base,
+	}
+
+	form, err := api.GetRegistrationForm(context.Background())
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if !form.CodeRequired {
+		t.Errorf("expected registration code to be required")
+	}
+
+	expected := []FormField{
+		{Id: 1, Name: "Country", Type: "text", Required: true},
+		{Id: 2, Name: "Newsletter", Type: "checkbox"},
+	}
+	if len(form.Fields) != len(expected) {
+		t.Fatalf("expected %d fields, got %+v", len(expected), form.Fields)
+	}
+	for i := range expected {
+		if form.Fields[i] != expected[i] {
+			t.Errorf("field %d = %+v, want %+v", i, form.Fields[i], expected[i])
+		}
+	}
+}
+
+func TestRegister_Success(t *testing.T) {
+	var form url.Values
+	base, _ := url.Parse("https://ctf.example.com")
+	api := &ApiClient{
+		client:  redirectingClient(t, registerPage, "/challenges", &form),
+		baseUrl: base,
+	}
+
+	err := api.Register(context.Background(), Registration{
+		Name:             "player",
+		Email:            "player@example.com",
+		Password:         "secret",
+		RegistrationCode: "code",
+		Fields:           map[int]string{1: "SE"},
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	for key, value := range map[string]string{
+		"name":              "player",
+		"email":             "player@example.com",
+		"password":          "secret",
+		"registration_code": "code",
+		"fields[1]":         "SE",
+		"nonce":             "test-nonce",
+	} {
+		if got := form.Get(key); got != value {
+			t.Errorf("expected %s=%q, got %q", key, value, got)
+		}
+	}
+}
+
+func TestRegister_Errors(t *testing.T) {
+	failedPage := `
+	<html>
+		<body>
+			<div class="alert alert-danger alert-dismissable" role="alert">
+				<span class="sr-only">Error:</span>
+				That user name is already taken
+				<button type="button" class="close" data-dismiss="alert">×</button>
+			</div>
+			<div class="alert alert-danger" role="alert">
+				Your email address is invalid
+			</div>
+		</body>
+	</html>`
+
+	mock := sequenceResponses(t, []*http.Response{
+		newResponse(200, registerPage),
+		newResponse(200, failedPage),
+	})
+
+	base, _ := url.Parse("https://ctf.example.com")
+	api := &ApiClient{
+		client:  mock,
+		baseUrl: base,
+	}
+
+	err := api.Register(context.Background(), Registration{Name: "player", Email: "x", Password: "secret"})
+	if !errors.Is(err, ErrRegistrationFailed) {
+		t.Fatalf("expected %v, got %v", ErrRegistrationFailed, err)
+	}
+	if !strings.Contains(err.Error(), "That user name is already taken, Your email address is invalid") {
+		t.Errorf("expected alerts in error, got %q", err.Error())
+	}
+	if strings.Contains(err.Error(), "×") || strings.Contains(err.Error(), "Error:") {
+		t.Errorf("expected close button and screen reader text to be dropped, got %q", err.Error())
+	}
+}
+
+func TestRegister_CAPTCHA(t *testing.T) {
+	page := `<html><head><title>Just a moment...</title></head></html>`
+
+	base, _ := url.Parse("https://ctf.example.com")
+	api := &ApiClient{
+		client:  mockResponse(t, newResponse(200, page)),
+		baseUrl: base,
+	}
+
+	err := api.Register(context.Background(), Registration{Name: "player", Password: "secret"})
+	if !errors.Is(err, ErrCaptchaRequired) {
+		t.Errorf("expected %v, got %v", ErrCaptchaRequired, err)
+	}
+}
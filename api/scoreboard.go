@@ -2,12 +2,10 @@ package api
 
 import (
 	"context"
-	"encoding/json"
-	"fmt"
 )
 
 func (c *ApiClient) GetScoreboard(ctx context.Context) ([]ScoreboardEntry, error) {
-	u := fmt.Sprintf("%s%s", c.baseUrl, scoreboardApiURL)
+	u := c.urlFor(scoreboardApiURL)
 
 	resp, err := c.get(ctx, u)
 
@@ -15,15 +13,5 @@ func (c *ApiClient) GetScoreboard(ctx context.Context) ([]ScoreboardEntry, error
 		return nil, err
 	}
 
-	defer resp.Body.Close()
-	var scoreboard ApiResponse[[]ScoreboardEntry]
-	if err := json.NewDecoder(resp.Body).Decode(&scoreboard); err != nil {
-		return nil, err
-	}
-
-	if scoreboard.Success != true {
-		return nil, ErrFailedFetchingBoard
-	}
-
-	return scoreboard.Data, nil
+	return decodeResponse[[]ScoreboardEntry](resp, ErrFailedFetchingBoard)
 }
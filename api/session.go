@@ -0,0 +1,151 @@
+package api
+
+import (
+	"bufio"
+	"fmt"
+	"net/http"
+	"net/http/cookiejar"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const netscapeCookieHeader = "# Netscape HTTP Cookie File"
+
+// ParseCookies parses cookies exported from a browser. Both Netscape
+// cookies.txt files and raw Cookie headers ("session=...; cf_clearance=...")
+// are accepted.
+func ParseCookies(input string) ([]*http.Cookie, error) {
+	input = strings.TrimSpace(input)
+	if input == "" {
+		return nil, fmt.Errorf(errNoCookies)
+	}
+
+	if isNetscapeCookieFile(input) {
+		return parseNetscapeCookies(input)
+	}
+
+	header := strings.TrimSpace(strings.TrimPrefix(input, "Cookie:"))
+	cookies, err := http.ParseCookie(header)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", errInvalidCookies, err)
+	}
+	if len(cookies) == 0 {
+		return nil, fmt.Errorf(errNoCookies)
+	}
+	return cookies, nil
+}
+
+func isNetscapeCookieFile(input string) bool {
+	if strings.HasPrefix(input, netscapeCookieHeader) {
+		return true
+	}
+	first, _, _ := strings.Cut(input, "\n")
+	return len(strings.Split(strings.TrimRight(first, "\r"), "\t")) == 7
+}
+
+func parseNetscapeCookies(input string) ([]*http.Cookie, error) {
+	var cookies []*http.Cookie
+
+	scanner := bufio.NewScanner(strings.NewReader(input))
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		line := strings.TrimRight(scanner.Text(), "\r")
+
+		httpOnly := strings.HasPrefix(line, "#HttpOnly_")
+		line = strings.TrimPrefix(line, "#HttpOnly_")
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		fields := strings.Split(line, "\t")
+		if len(fields) != 7 {
+			return nil, fmt.Errorf("%s: line %d has %d fields, expected 7", errInvalidCookies, lineNo, len(fields))
+		}
+
+		cookie := &http.Cookie{
+			Domain:   fields[0],
+			Path:     fields[2],
+			Secure:   strings.EqualFold(fields[3], "TRUE"),
+			Name:     fields[5],
+			Value:    fields[6],
+			HttpOnly: httpOnly,
+		}
+		if expires, err := strconv.ParseInt(fields[4], 10, 64); err == nil && expires > 0 {
+			cookie.Expires = time.Unix(expires, 0)
+		}
+
+		cookies = append(cookies, cookie)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if len(cookies) == 0 {
+		return nil, fmt.Errorf(errNoCookies)
+	}
+	return cookies, nil
+}
+
+// ImportSession loads cookies from a browser session, such as session and
+// cf_clearance, into the client. Cookies for other domains are ignored. The
+// user agent should match the browser the cookies were issued to, as
+// Cloudflare ties cf_clearance to it.
+func (c *ApiClient) ImportSession(cookies []*http.Cookie, userAgent string) error {
+	if c.jar == nil {
+		jar, err := cookiejar.New(nil)
+		if err != nil {
+			return err
+		}
+		c.jar = jar
+	}
+
+	var imported []*http.Cookie
+	for _, cookie := range cookies {
+		if !domainMatches(cookie.Domain, c.baseUrl.Hostname()) {
+			continue
+		}
+		imported = append(imported, &http.Cookie{
+			Name:     cookie.Name,
+			Value:    cookie.Value,
+			Path:     "/",
+			Secure:   cookie.Secure,
+			HttpOnly: cookie.HttpOnly,
+			Expires:  cookie.Expires,
+		})
+	}
+
+	if len(imported) == 0 {
+		return fmt.Errorf("%s for %s", errNoCookies, c.baseUrl.Hostname())
+	}
+
+	c.jar.SetCookies(c.baseUrl, imported)
+	if userAgent != "" {
+		c.userAgent = userAgent
+	}
+	return nil
+}
+
+// SessionCookies returns the cookies the client currently sends to the CTFd
+// instance, for example to persist an imported session.
+func (c *ApiClient) SessionCookies() []*http.Cookie {
+	if c.jar == nil {
+		return nil
+	}
+	return c.jar.Cookies(c.baseUrl)
+}
+
+// CookieHeader formats cookies as the value of a Cookie header, which can be
+// read back with ParseCookies.
+func CookieHeader(cookies []*http.Cookie) string {
+	parts := make([]string, len(cookies))
+	for i, c := range cookies {
+		parts[i] = fmt.Sprintf("%s=%s", c.Name, c.Value)
+	}
+	return strings.Join(parts, "; ")
+}
+
+func domainMatches(domain, host string) bool {
+	domain = strings.ToLower(strings.TrimPrefix(domain, "."))
+	host = strings.ToLower(host)
+	return domain == "" || domain == host || strings.HasSuffix(host, "."+domain)
+}
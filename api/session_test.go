@@ -0,0 +1,111 @@
+package api
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestParseCookies(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected map[string]string
+		isError  bool
+	}{
+		{
+			name:     "Cookie header",
+			input:    "session=abc123; cf_clearance=xyz",
+			expected: map[string]string{"session": "abc123", "cf_clearance": "xyz"},
+		},
+		{
+			name:     "Cookie header with name",
+			input:    "Cookie: session=abc123",
+			expected: map[string]string{"session": "abc123"},
+		},
+		{
+			name: "Netscape cookies.txt",
+			input: strings.Join([]string{
+				"# Netscape HTTP Cookie File",
+				"",
+				"#HttpOnly_ctf.example.com\tFALSE\t/\tTRUE\t0\tsession\tabc123",
+				".example.com\tTRUE\t/\tTRUE\t1999999999\tcf_clearance\txyz",
+			}, "\n"),
+			expected: map[string]string{"session": "abc123", "cf_clearance": "xyz"},
+		},
+		{
+			name:    "Netscape line with missing fields",
+			input:   "# Netscape HTTP Cookie File\nctf.example.com\tFALSE\t/\tsession",
+			isError: true,
+		},
+		{
+			name:    "Empty",
+			input:   "  ",
+			isError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cookies, err := ParseCookies(tt.input)
+			if tt.isError {
+				if err == nil {
+					t.Errorf("expected error, got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			got := map[string]string{}
+			for _, c := range cookies {
+				got[c.Name] = c.Value
+			}
+			for name, value := range tt.expected {
+				if got[name] != value {
+					t.Errorf("expected cookie %s=%q, got %q", name, value, got[name])
+				}
+			}
+			if len(got) != len(tt.expected) {
+				t.Errorf("expected %d cookies, got %d", len(tt.expected), len(got))
+			}
+		})
+	}
+}
+
+func TestImportSession(t *testing.T) {
+	base, _ := url.Parse("https://ctf.example.com")
+	api := &ApiClient{baseUrl: base}
+
+	cookies, _ := ParseCookies(strings.Join([]string{
+		"ctf.example.com\tFALSE\t/\tTRUE\t0\tsession\tabc123",
+		".example.com\tTRUE\t/\tTRUE\t0\tcf_clearance\txyz",
+		".other.com\tTRUE\t/\tTRUE\t0\tsession\tunrelated",
+	}, "\n"))
+
+	if err := api.ImportSession(cookies, "Mozilla/5.0"); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	got := map[string]string{}
+	for _, c := range api.SessionCookies() {
+		got[c.Name] = c.Value
+	}
+	if got["session"] != "abc123" || got["cf_clearance"] != "xyz" || len(got) != 2 {
+		t.Errorf("unexpected imported cookies %v", got)
+	}
+	if api.userAgent != "Mozilla/5.0" {
+		t.Errorf("expected user agent to be set, got %q", api.userAgent)
+	}
+}
+
+func TestImportSession_NoMatchingCookies(t *testing.T) {
+	base, _ := url.Parse("https://ctf.example.com")
+	api := &ApiClient{baseUrl: base}
+
+	cookies, _ := ParseCookies(".other.com\tTRUE\t/\tTRUE\t0\tsession\tunrelated")
+	if err := api.ImportSession(cookies, ""); err == nil {
+		t.Errorf("expected error, got none")
+	}
+}
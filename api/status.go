@@ -0,0 +1,199 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type CTFState int
+
+const (
+	CTFRunning CTFState = iota
+	CTFNotStarted
+	CTFEnded
+	CTFPaused
+)
+
+func (s CTFState) String() string {
+	switch s {
+	case CTFNotStarted:
+		return "not started"
+	case CTFEnded:
+		return "ended"
+	case CTFPaused:
+		return "paused"
+	default:
+		return "running"
+	}
+}
+
+// CTFStatus is whether the CTF is running, as shown on the challenges page.
+// Start and End are zero when the admins didn't set them.
+type CTFStatus struct {
+	// Name is the name of the CTF, empty when the page doesn't show it.
+	Name  string
+	State CTFState
+	Start time.Time
+	End   time.Time
+	// Messages are the notices CTFd shows above the challenges, such as
+	// "Example CTF has not started yet".
+	Messages []string
+}
+
+var (
+	ctfStartRegex = regexp.MustCompile(`['"]start['"]\s*:\s*(\d+)`)
+	ctfEndRegex   = regexp.MustCompile(`['"]end['"]\s*:\s*(\d+)`)
+	ctfNameRegex  = regexp.MustCompile(`['"]ctf_?[nN]ame['"]\s*:\s*"([^"]+)"`)
+	// titleSeparator splits page titles like "Challenges - Example CTF".
+	titleSeparator = regexp.MustCompile(`\s+[-|–—:]\s+`)
+	// ctfNoticeRegex matches the notices naming the CTF.
+	ctfNoticeRegex = regexp.MustCompile(`^(.+?) (?:has not started yet|has not begun yet|has ended|is paused)$`)
+)
+
+// GetCTFStatus reads the challenges page, which unlike the API still loads
+// before the CTF starts, to find out whether the CTF is running and when it
+// starts and ends.
+func (c *ApiClient) GetCTFStatus(ctx context.Context) (*CTFStatus, error) {
+	if c.token != "" {
+		return c.ctfStatusFromAPI(ctx)
+	}
+
+	resp, err := c.get(ctx, c.urlFor(challengesURL))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", errFailedFetchingStatus, err)
+	}
+	defer resp.Body.Close()
+
+	bodyBytes, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", errFailedToReadResponseBody, err)
+	}
+
+	return parseCTFStatus(string(bodyBytes), time.Now()), nil
+}
+
+func parseCTFStatus(body string, now time.Time) *CTFStatus {
+	status := &CTFStatus{
+		Start: extractTime(ctfStartRegex, body),
+		End:   extractTime(ctfEndRegex, body),
+	}
+
+	status.Messages = append(extractAlerts(body, "alert-danger"), extractAlerts(body, "alert-info")...)
+	status.Name = extractCTFName(body, status.Messages)
+	if status.State = stateFromMessages(status.Messages); status.State != CTFRunning {
+		return status
+	}
+
+	// Themes that don't show the notices still include the times.
+	switch {
+	case !status.Start.IsZero() && now.Before(status.Start):
+		status.State = CTFNotStarted
+	case !status.End.IsZero() && now.After(status.End):
+		status.State = CTFEnded
+	}
+
+	return status
+}
+
+// ctfStatusFromAPI finds out whether the CTF is running from the challenges
+// API. Tokens only authenticate API requests, so the challenges page can't
+// be read. While the CTF isn't running CTFd refuses the challenges with the
+// same notice the page shows, but the start and end times are unknown.
+func (c *ApiClient) ctfStatusFromAPI(ctx context.Context) (*CTFStatus, error) {
+	resp, err := c.get(ctx, c.urlFor(challengesApiURL))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", errFailedFetchingStatus, err)
+	}
+
+	_, err = decodeResponse[json.RawMessage](resp, errors.New(errFailedFetchingStatus))
+	if err == nil {
+		return &CTFStatus{State: CTFRunning}, nil
+	}
+
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusForbidden {
+		return nil, err
+	}
+
+	var messages []string
+	if apiErr.Message != "" {
+		messages = append(messages, apiErr.Message)
+	}
+	messages = append(messages, apiErr.Errors...)
+
+	status := &CTFStatus{
+		Name:     extractCTFName("", messages),
+		State:    stateFromMessages(messages),
+		Messages: messages,
+	}
+	// The challenges are only refused to players while the CTF isn't
+	// running.
+	if status.State == CTFRunning {
+		status.State = CTFNotStarted
+	}
+	return status, nil
+}
+
+// stateFromMessages reads the state of the CTF from the notices CTFd shows
+// while it isn't running.
+func stateFromMessages(messages []string) CTFState {
+	for _, message := range messages {
+		switch {
+		case strings.HasSuffix(message, "has not started yet"), strings.HasSuffix(message, "has not begun yet"):
+			return CTFNotStarted
+		case strings.HasSuffix(message, "has ended"):
+			return CTFEnded
+		case strings.HasSuffix(message, "is paused"):
+			return CTFPaused
+		}
+	}
+	return CTFRunning
+}
+
+// extractCTFName finds the name of the CTF in the page's config, in its title
+// without the name of the page, or in the notices about the CTF's state.
+func extractCTFName(body string, messages []string) string {
+	if m := ctfNameRegex.FindStringSubmatch(body); m != nil {
+		return strings.TrimSpace(m[1])
+	}
+
+	if title, err := extractTitle(body); err == nil {
+		var parts []string
+		for _, part := range titleSeparator.Split(title, -1) {
+			if !strings.EqualFold(part, "Challenges") {
+				parts = append(parts, part)
+			}
+		}
+		if len(parts) > 0 {
+			return strings.Join(parts, " - ")
+		}
+	}
+
+	for _, message := range messages {
+		if m := ctfNoticeRegex.FindStringSubmatch(message); m != nil {
+			return m[1]
+		}
+	}
+	return ""
+}
+
+func extractTime(regex *regexp.Regexp, body string) time.Time {
+	match := regex.FindStringSubmatch(body)
+	if match == nil {
+		return time.Time{}
+	}
+
+	seconds, err := strconv.ParseInt(match[1], 10, 64)
+	if err != nil || seconds == 0 {
+		return time.Time{}
+	}
+	return time.Unix(seconds, 0)
+}
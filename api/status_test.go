@@ -0,0 +1,192 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+)
+
+func challengesPage(start, end, alerts string) string {
+	return `
+	<html>
+		<head>
+			<title>Challenges - Example CTF</title>
+			<script type="text/javascript">
+				window.init = {
+					'urlRoot': "",
+					'csrfNonce': "abc123",
+					'userMode': "teams",
+					'start': ` + start + `,
+					'end': ` + end + `,
+				}
+			</script>
+		</head>
+		<body>` + alerts + `</body>
+	</html>`
+}
+
+func TestParseCTFStatus(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+
+	tests := []struct {
+		name     string
+		body     string
+		expected CTFState
+		start    int64
+		end      int64
+	}{
+		{
+			name:     "Not started",
+			body:     challengesPage("1700003600", "1700090000", `<div class="alert alert-danger"><span class="sr-only">Error:</span> Example CTF has not started yet</div>`),
+			expected: CTFNotStarted,
+			start:    1700003600,
+			end:      1700090000,
+		},
+		{
+			name:     "Ended",
+			body:     challengesPage("1690000000", "1690090000", `<div class="alert alert-danger">Example CTF has ended</div>`),
+			expected: CTFEnded,
+			start:    1690000000,
+			end:      1690090000,
+		},
+		{
+			name:     "Paused",
+			body:     challengesPage("1690000000", "1790000000", `<div class="alert alert-info">Example CTF is paused</div>`),
+			expected: CTFPaused,
+			start:    1690000000,
+			end:      1790000000,
+		},
+		{
+			name:     "Running",
+			body:     challengesPage("1690000000", "1790000000", ""),
+			expected: CTFRunning,
+			start:    1690000000,
+			end:      1790000000,
+		},
+		{
+			name:     "No times",
+			body:     challengesPage("null", "null", ""),
+			expected: CTFRunning,
+		},
+		{
+			name:     "Not started without notice",
+			body:     challengesPage("1700000060", "null", ""),
+			expected: CTFNotStarted,
+			start:    1700000060,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status := parseCTFStatus(tt.body, now)
+			if status.State != tt.expected {
+				t.Errorf("expected state %v, got %v", tt.expected, status.State)
+			}
+			if tt.start != 0 && status.Start.Unix() != tt.start {
+				t.Errorf("expected start %d, got %v", tt.start, status.Start)
+			}
+			if tt.start == 0 && !status.Start.IsZero() {
+				t.Errorf("expected no start, got %v", status.Start)
+			}
+			if tt.end != 0 && status.End.Unix() != tt.end {
+				t.Errorf("expected end %d, got %v", tt.end, status.End)
+			}
+		})
+	}
+}
+
+func TestGetCTFStatus(t *testing.T) {
+	body := challengesPage("4102444800", "null", `<div class="alert alert-danger">Example CTF has not started yet</div>`)
+
+	base, _ := url.Parse("https://ctf.example.com")
+	api := &ApiClient{client: mockResponse(t, newResponse(200, body)), baseUrl: base}
+
+	status, err := api.GetCTFStatus(context.Background())
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if status.State != CTFNotStarted {
+		t.Errorf("expected not started, got %v", status.State)
+	}
+	if status.Name != "Example CTF" {
+		t.Errorf("expected the name of the CTF, got %q", status.Name)
+	}
+	if len(status.Messages) != 1 || status.Messages[0] != "Example CTF has not started yet" {
+		t.Errorf("unexpected messages %q", status.Messages)
+	}
+}
+
+func TestGetCTFStatus_Token(t *testing.T) {
+	tests := []struct {
+		name     string
+		resp     *http.Response
+		expected CTFState
+		ctfName  string
+	}{
+		{"Running", newResponse(200, `{"success": true, "data": []}`), CTFRunning, ""},
+		{"Not started", newResponse(403, `{"success": false, "message": "Example CTF has not started yet"}`), CTFNotStarted, "Example CTF"},
+		{"Ended", newResponse(403, `{"success": false, "message": "Example CTF has ended"}`), CTFEnded, "Example CTF"},
+		{"No notice", newResponse(403, `{"success": false}`), CTFNotStarted, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := &mockClient{
+				t: t,
+				doFunc: func(req *http.Request) (*http.Response, error) {
+					// The challenges page needs a session, a token is only
+					// good for the API.
+					if req.URL.Path != challengesApiURL {
+						t.Errorf("unexpected request to %s", req.URL.Path)
+					}
+					if req.Header.Get("Authorization") != "Token test-token" {
+						t.Errorf("expected the token to be sent")
+					}
+					tt.resp.Header.Set("Content-Type", "application/json")
+					return tt.resp, nil
+				},
+			}
+
+			base, _ := url.Parse("https://ctf.example.com")
+			api := &ApiClient{client: mock, baseUrl: base}
+			api.SetToken("test-token")
+
+			status, err := api.GetCTFStatus(context.Background())
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			if status.State != tt.expected {
+				t.Errorf("expected %v, got %v", tt.expected, status.State)
+			}
+			if status.Name != tt.ctfName {
+				t.Errorf("expected name %q, got %q", tt.ctfName, status.Name)
+			}
+		})
+	}
+}
+
+func TestExtractCTFName(t *testing.T) {
+	tests := []struct {
+		name     string
+		body     string
+		messages []string
+		expected string
+	}{
+		{"Config", `<title>Challenges</title><script>window.init = {'ctf_name': "Config CTF"}</script>`, nil, "Config CTF"},
+		{"Title", `<title>Challenges - Example CTF</title>`, nil, "Example CTF"},
+		{"Title without page", `<title>Example CTF</title>`, nil, "Example CTF"},
+		{"Title with other separator", `<title>Example CTF | Challenges</title>`, nil, "Example CTF"},
+		{"Notice", `<title>Challenges</title>`, []string{"Example CTF has not started yet"}, "Example CTF"},
+		{"Unknown", `<title>Challenges</title>`, []string{"Something else"}, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if name := extractCTFName(tt.body, tt.messages); name != tt.expected {
+				t.Errorf("extractCTFName() = %q, want %q", name, tt.expected)
+			}
+		})
+	}
+}
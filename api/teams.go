@@ -0,0 +1,73 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"strings"
+)
+
+func (c *ApiClient) GetMyTeam(ctx context.Context) (*Team, error) {
+	resp, err := c.get(ctx, c.urlFor(teamsMeApiURL))
+	if err != nil {
+		return nil, err
+	}
+
+	team, err := decodeResponse[Team](resp, errors.New(errFailedFetchingTeam))
+	if err != nil {
+		return nil, err
+	}
+
+	return &team, nil
+}
+
+// TeamRequired reports whether the instance runs in team mode and the user
+// has yet to create or join a team. CTFd then redirects the challenges page
+// to /team. Tokens only authenticate API requests, and the team forms can't
+// be submitted with one, so token profiles are never asked for a team.
+func (c *ApiClient) TeamRequired(ctx context.Context) (bool, error) {
+	if c.token != "" {
+		return false, nil
+	}
+
+	resp, err := c.get(ctx, c.urlFor(challengesURL))
+	if err != nil {
+		return false, err
+	}
+	resp.Body.Close()
+
+	return resp.Request != nil && resp.Request.URL.Path == c.baseUrl.Path+teamURL, nil
+}
+
+// CreateTeam creates a team and makes the logged in user its captain.
+func (c *ApiClient) CreateTeam(ctx context.Context, name, password string) error {
+	if strings.TrimSpace(name) == "" {
+		return ErrInvalidTeamName
+	}
+
+	values := url.Values{
+		"name":     {name},
+		"password": {password},
+	}
+
+	_, err := c.submitForm(ctx, teamsNewURL, values, ErrCreateTeamFailed)
+	return err
+}
+
+// JoinTeam joins an existing team with its password.
+func (c *ApiClient) JoinTeam(ctx context.Context, name, password string) error {
+	if strings.TrimSpace(name) == "" {
+		return ErrInvalidTeamName
+	}
+	if password == "" {
+		return ErrInvalidPassword
+	}
+
+	values := url.Values{
+		"name":     {name},
+		"password": {password},
+	}
+
+	_, err := c.submitForm(ctx, teamsJoinURL, values, ErrJoinTeamFailed)
+	return err
+}
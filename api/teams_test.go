@@ -0,0 +1,152 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"testing"
+)
+
+const teamPage = `
+<html>
+	<head>
+		<title>Team</title>
+	</head>
+	<body>
+		<form method="post">
+			<input id="name" name="name" type="text">
+			<input id="password" name="password" type="password">
+			<input id="nonce" name="nonce" type="hidden" value="test-nonce">
+		</form>
+	</body>
+</html>`
+
+func TestTeamRequired(t *testing.T) {
+	tests := []struct {
+		name     string
+		base     string
+		final    string
+		expected bool
+	}{
+		{"No team", "https://ctf.example.com", "https://ctf.example.com/team", true},
+		{"Has team", "https://ctf.example.com", "https://ctf.example.com/challenges", false},
+		{"Path prefix", "https://example.com/ctf", "https://example.com/ctf/team", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := &mockClient{
+				t: t,
+				doFunc: func(req *http.Request) (*http.Response, error) {
+					resp := newResponse(200, "<html></html>")
+					resp.Request = req.Clone(req.Context())
+					resp.Request.URL, _ = url.Parse(tt.final)
+					return resp, nil
+				},
+			}
+
+			base, _ := url.Parse(tt.base)
+			api := &ApiClient{client: mock, baseUrl: base}
+
+			required, err := api.TeamRequired(context.Background())
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			if required != tt.expected {
+				t.Errorf("expected %v, got %v", tt.expected, required)
+			}
+		})
+	}
+}
+
+func TestTeamRequired_Token(t *testing.T) {
+	mock := &mockClient{
+		t: t,
+		doFunc: func(req *http.Request) (*http.Response, error) {
+			t.Errorf("unexpected request to %s", req.URL.Path)
+			return newResponse(200, ""), nil
+		},
+	}
+
+	base, _ := url.Parse("https://ctf.example.com")
+	api := &ApiClient{client: mock, baseUrl: base}
+	api.SetToken("test-token")
+
+	required, err := api.TeamRequired(context.Background())
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if required {
+		t.Error("expected no team to be required")
+	}
+}
+
+func TestCreateTeam_Success(t *testing.T) {
+	var form url.Values
+	base, _ := url.Parse("https://ctf.example.com")
+	api := &ApiClient{
+		client:  redirectingClient(t, teamPage, "/challenges", &form),
+		baseUrl: base,
+	}
+
+	if err := api.CreateTeam(context.Background(), "hackers", "secret"); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if form.Get("name") != "hackers" || form.Get("password") != "secret" || form.Get("nonce") != "test-nonce" {
+		t.Errorf("unexpected form %v", form)
+	}
+}
+
+func TestJoinTeam_WrongPassword(t *testing.T) {
+	failedPage := `<div class="alert alert-danger">That information is incorrect</div>`
+
+	mock := sequenceResponses(t, []*http.Response{
+		newResponse(200, teamPage),
+		newResponse(403, failedPage),
+	})
+
+	base, _ := url.Parse("https://ctf.example.com")
+	api := &ApiClient{client: mock, baseUrl: base}
+
+	err := api.JoinTeam(context.Background(), "hackers", "wrong")
+	if !errors.Is(err, ErrJoinTeamFailed) {
+		t.Fatalf("expected %v, got %v", ErrJoinTeamFailed, err)
+	}
+	if err.Error() != "failed to join team: That information is incorrect" {
+		t.Errorf("unexpected error %q", err.Error())
+	}
+}
+
+func TestJoinTeam_EmptyName(t *testing.T) {
+	api := &ApiClient{client: &mockClient{t: t}}
+
+	if err := api.JoinTeam(context.Background(), " ", "secret"); !errors.Is(err, ErrInvalidTeamName) {
+		t.Errorf("expected %v, got %v", ErrInvalidTeamName, err)
+	}
+}
+
+func TestGetMyTeam(t *testing.T) {
+	responseBody := `{
+		"success": true,
+		"data": {
+			"id": 3,
+			"name": "hackers",
+			"captain_id": 7,
+			"members": [7, 8],
+			"place": "1st",
+			"score": 1500
+		}
+	}`
+
+	base, _ := url.Parse("https://ctf.example.com")
+	api := &ApiClient{client: mockResponse(t, newResponse(200, responseBody)), baseUrl: base}
+
+	team, err := api.GetMyTeam(context.Background())
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if team.Name != "hackers" || team.Place != "1st" || team.Score != 1500 || len(team.Members) != 2 {
+		t.Errorf("unexpected team %+v", team)
+	}
+}
@@ -0,0 +1,48 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// CreateToken creates a personal access token. A zero expiration leaves it
+// to CTFd, which defaults to 30 days.
+func (c *ApiClient) CreateToken(ctx context.Context, description string, expiration time.Time) (*Token, error) {
+	request := TokenRequest{Description: description}
+	if !expiration.IsZero() {
+		request.Expiration = expiration.Format(time.DateOnly)
+	}
+
+	resp, err := c.sendJSON(ctx, "POST", c.urlFor(tokensApiURL), request)
+	if err != nil {
+		return nil, err
+	}
+
+	token, err := decodeResponse[Token](resp, errors.New(errFailedCreatingToken))
+	if err != nil {
+		return nil, err
+	}
+
+	return &token, nil
+}
+
+func (c *ApiClient) GetTokens(ctx context.Context) ([]Token, error) {
+	resp, err := c.get(ctx, c.urlFor(tokensApiURL))
+	if err != nil {
+		return nil, err
+	}
+
+	return decodeResponse[[]Token](resp, errors.New(errFailedFetchingTokens))
+}
+
+func (c *ApiClient) DeleteToken(ctx context.Context, id int) error {
+	resp, err := c.sendJSON(ctx, "DELETE", c.urlFor(fmt.Sprintf("%s/%d", tokensApiURL, id)), nil)
+	if err != nil {
+		return err
+	}
+
+	_, err = decodeResponse[struct{}](resp, fmt.Errorf("%s: %d", errFailedDeletingToken, id))
+	return err
+}
@@ -0,0 +1,158 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+)
+
+const challengesPageWithNonce = `<script>var init = {'csrfNonce': "abc123",}</script>`
+
+func TestCreateToken_Session(t *testing.T) {
+	var request TokenRequest
+	var nonce string
+	calls := 0
+	mock := &mockClient{
+		t: t,
+		doFunc: func(req *http.Request) (*http.Response, error) {
+			calls++
+			if calls == 1 {
+				resp := newResponse(200, challengesPageWithNonce)
+				resp.Request = req
+				return resp, nil
+			}
+
+			if req.Method != "POST" || req.URL.Path != "/api/v1/tokens" {
+				t.Errorf("unexpected request %s %s", req.Method, req.URL.Path)
+			}
+			nonce = req.Header.Get(csrfTokenHeaderName)
+			body, _ := io.ReadAll(req.Body)
+			json.Unmarshal(body, &request)
+
+			resp := newResponse(200, `{"success": true, "data": {"id": 4, "type": "user", "description": "ctfd-cli", "expiration": "2030-01-02T00:00:00+00:00", "value": "ctfd_secret"}}`)
+			resp.Request = req
+			return resp, nil
+		},
+	}
+
+	base, _ := url.Parse("https://ctf.example.com")
+	api := &ApiClient{client: mock, baseUrl: base}
+
+	token, err := api.CreateToken(context.Background(), "ctfd-cli", time.Date(2030, 1, 2, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if token.Id != 4 || token.Value != "ctfd_secret" {
+		t.Errorf("unexpected token %+v", token)
+	}
+	if nonce != "abc123" {
+		t.Errorf("expected CSRF nonce abc123, got %q", nonce)
+	}
+	if request.Description != "ctfd-cli" || request.Expiration != "2030-01-02" {
+		t.Errorf("unexpected request %+v", request)
+	}
+}
+
+func TestCreateToken_TokenAuth(t *testing.T) {
+	mock := &mockClient{
+		t: t,
+		doFunc: func(req *http.Request) (*http.Response, error) {
+			if got := req.Header.Get("Authorization"); got != "Token ctfd_old" {
+				t.Errorf("expected token authorization, got %q", got)
+			}
+			if req.Header.Get(csrfTokenHeaderName) != "" {
+				t.Errorf("expected no CSRF nonce with token authentication")
+			}
+			resp := newResponse(200, `{"success": true, "data": {"id": 5, "value": "ctfd_new"}}`)
+			resp.Request = req
+			return resp, nil
+		},
+	}
+
+	base, _ := url.Parse("https://ctf.example.com")
+	api := &ApiClient{client: mock, baseUrl: base}
+	api.SetToken("ctfd_old")
+
+	token, err := api.CreateToken(context.Background(), "", time.Time{})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if token.Value != "ctfd_new" {
+		t.Errorf("unexpected token %+v", token)
+	}
+}
+
+func TestGetTokens(t *testing.T) {
+	mock := &mockClient{
+		t: t,
+		doFunc: func(req *http.Request) (*http.Response, error) {
+			if req.Header.Get("Content-Type") != "application/json" {
+				t.Errorf("expected JSON content type for token authentication")
+			}
+			resp := newResponse(200, `{"success": true, "data": [{"id": 1, "description": "laptop"}, {"id": 2}]}`)
+			resp.Request = req
+			return resp, nil
+		},
+	}
+
+	base, _ := url.Parse("https://ctf.example.com")
+	api := &ApiClient{client: mock, baseUrl: base}
+	api.SetToken("ctfd_secret")
+
+	tokens, err := api.GetTokens(context.Background())
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(tokens) != 2 || tokens[0].Description != "laptop" {
+		t.Errorf("unexpected tokens %+v", tokens)
+	}
+}
+
+func TestDeleteToken(t *testing.T) {
+	mock := sequenceResponses(t, []*http.Response{
+		newResponse(200, challengesPageWithNonce),
+		newResponse(404, `{"success": false, "message": "Token not found"}`),
+	})
+
+	base, _ := url.Parse("https://ctf.example.com")
+	api := &ApiClient{client: mock, baseUrl: base}
+
+	err := api.DeleteToken(context.Background(), 9)
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("expected *APIError, got %v", err)
+	}
+	if apiErr.Endpoint != "DELETE /api/v1/tokens/9" || apiErr.Message != "Token not found" {
+		t.Errorf("unexpected error %+v", apiErr)
+	}
+}
+
+func TestTokenOnlySentToAPI(t *testing.T) {
+	mock := &mockClient{
+		t: t,
+		doFunc: func(req *http.Request) (*http.Response, error) {
+			if got := req.Header.Get("Authorization"); got != "" {
+				t.Errorf("expected no token for %s, got %q", req.URL, got)
+			}
+			if got := req.Header.Get("Content-Type"); got != "" {
+				t.Errorf("expected no content type for %s, got %q", req.URL, got)
+			}
+			return newResponse(200, "file contents"), nil
+		},
+	}
+
+	base, _ := url.Parse("https://ctf.example.com")
+	api := &ApiClient{client: mock, baseUrl: base}
+	api.SetToken("ctfd_secret")
+
+	for _, file := range []string{"https://cdn.example.com/api/chall.zip", "/files/abc/chall.zip"} {
+		if _, err := api.DownloadFile(context.Background(), file); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+	}
+}
@@ -0,0 +1,233 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+type Options struct {
+	// Timeout is how long a single attempt of a request waits for the
+	// response headers.
+	Timeout time.Duration
+	// Retries is how many times a failed idempotent request is retried.
+	Retries int
+	// BaseDelay is the initial backoff delay, doubled for every retry up to
+	// MaxDelay.
+	BaseDelay time.Duration
+	MaxDelay  time.Duration
+	// BreakerThreshold is the number of consecutive failures after which
+	// requests are paused for BreakerCooldown.
+	BreakerThreshold int
+	BreakerCooldown  time.Duration
+	Network          NetworkOptions
+}
+
+func DefaultOptions() Options {
+	return Options{
+		Timeout:          5 * time.Second,
+		Retries:          3,
+		BaseDelay:        250 * time.Millisecond,
+		MaxDelay:         4 * time.Second,
+		BreakerThreshold: 5,
+		BreakerCooldown:  15 * time.Second,
+	}
+}
+
+// Budget returns an upper bound for how long a request may take including
+// all retries and backoff.
+func (o Options) Budget() time.Duration {
+	return o.Timeout*time.Duration(o.Retries+1) + o.MaxDelay*time.Duration(o.Retries)
+}
+
+type retryTransport struct {
+	base    http.RoundTripper
+	opts    Options
+	breaker *breaker
+	sleep   func(ctx context.Context, d time.Duration) error
+}
+
+func newRetryTransport(base http.RoundTripper, opts Options) *retryTransport {
+	return &retryTransport{
+		base:    base,
+		opts:    opts,
+		breaker: &breaker{threshold: opts.BreakerThreshold, cooldown: opts.BreakerCooldown, now: time.Now},
+		sleep:   sleepContext,
+	}
+}
+
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	for attempt := 0; ; attempt++ {
+		if err := t.breaker.allow(); err != nil {
+			return nil, err
+		}
+
+		r := req
+		if attempt > 0 && req.Body != nil {
+			if req.GetBody == nil {
+				return nil, errors.New("cannot retry request without GetBody")
+			}
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			r = req.Clone(req.Context())
+			r.Body = body
+		}
+
+		resp, err := t.roundTrip(r)
+
+		if req.Context().Err() != nil {
+			return resp, err
+		}
+
+		failed := err != nil || resp.StatusCode >= http.StatusInternalServerError
+		t.breaker.record(!failed)
+
+		if !failed || attempt >= t.opts.Retries || !retryable(req, err) {
+			return resp, err
+		}
+
+		if resp != nil {
+			io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		}
+
+		if err := t.sleep(req.Context(), t.backoff(attempt)); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// roundTrip performs a single attempt with the per attempt timeout. The
+// timeout only covers waiting for the response headers, so large downloads
+// that keep receiving data are bounded by the context of the caller alone.
+func (t *retryTransport) roundTrip(req *http.Request) (*http.Response, error) {
+	if t.opts.Timeout <= 0 {
+		return t.base.RoundTrip(req)
+	}
+
+	ctx, cancel := context.WithCancel(req.Context())
+	var timedOut atomic.Bool
+	timer := time.AfterFunc(t.opts.Timeout, func() {
+		timedOut.Store(true)
+		cancel()
+	})
+	resp, err := t.base.RoundTrip(req.WithContext(ctx))
+	timer.Stop()
+
+	if timedOut.Load() {
+		if err == nil {
+			resp.Body.Close()
+		}
+		cancel()
+		return nil, fmt.Errorf("no response within %s: %w", t.opts.Timeout, context.DeadlineExceeded)
+	}
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+
+	resp.Body = &cancelBody{ReadCloser: resp.Body, cancel: cancel}
+	return resp, nil
+}
+
+func (t *retryTransport) backoff(attempt int) time.Duration {
+	d := t.opts.BaseDelay << attempt
+	if d <= 0 || d > t.opts.MaxDelay {
+		d = t.opts.MaxDelay
+	}
+	if d <= 0 {
+		return 0
+	}
+	// Equal jitter: at least half the delay, so retries from many clients
+	// spread out without collapsing to zero.
+	return d/2 + rand.N(d/2+1)
+}
+
+// retryable reports whether a failed request may be sent again. Idempotent
+// requests are always retried, other requests only when they never reached
+// the server.
+func retryable(req *http.Request, err error) bool {
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return true
+	}
+
+	var opErr *net.OpError
+	return err != nil && errors.As(err, &opErr) && opErr.Op == "dial"
+}
+
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+type cancelBody struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (b *cancelBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.cancel()
+	return err
+}
+
+// breaker stops sending requests for a while after too many consecutive
+// failures, so a server that is clearly down isn't hammered with retries.
+type breaker struct {
+	mu        sync.Mutex
+	threshold int
+	cooldown  time.Duration
+	failures  int
+	openUntil time.Time
+	now       func() time.Time
+}
+
+func (b *breaker) allow() error {
+	if b.threshold <= 0 {
+		return nil
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.now().Before(b.openUntil) {
+		return ErrCircuitOpen
+	}
+	return nil
+}
+
+func (b *breaker) record(success bool) {
+	if b.threshold <= 0 {
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if success {
+		b.failures = 0
+		return
+	}
+
+	b.failures++
+	if b.failures >= b.threshold {
+		b.openUntil = b.now().Add(b.cooldown)
+	}
+}
@@ -0,0 +1,231 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+type roundTripFunc func(req *http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func newTestTransport(base http.RoundTripper, opts Options) *retryTransport {
+	t := newRetryTransport(base, opts)
+	t.sleep = func(ctx context.Context, d time.Duration) error { return nil }
+	return t
+}
+
+func testOptions() Options {
+	opts := DefaultOptions()
+	opts.Timeout = 0
+	opts.BreakerThreshold = 0
+	return opts
+}
+
+func TestRetryTransport_RetriesGetOnServerError(t *testing.T) {
+	calls := 0
+	base := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		calls++
+		if calls < 3 {
+			return newResponse(503, "unavailable"), nil
+		}
+		return newResponse(200, "ok"), nil
+	})
+
+	req, _ := http.NewRequest("GET", "https://ctf.example.com/api/v1/challenges", nil)
+	resp, err := newTestTransport(base, testOptions()).RoundTrip(req)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if resp.StatusCode != 200 {
+		t.Errorf("expected status 200, got %d", resp.StatusCode)
+	}
+	if calls != 3 {
+		t.Errorf("expected 3 calls, got %d", calls)
+	}
+}
+
+func TestRetryTransport_GivesUpAfterRetries(t *testing.T) {
+	calls := 0
+	base := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		calls++
+		return newResponse(502, "bad gateway"), nil
+	})
+
+	opts := testOptions()
+	opts.Retries = 2
+
+	req, _ := http.NewRequest("GET", "https://ctf.example.com/api/v1/scoreboard", nil)
+	resp, err := newTestTransport(base, opts).RoundTrip(req)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if resp.StatusCode != 502 {
+		t.Errorf("expected status 502, got %d", resp.StatusCode)
+	}
+	if calls != 3 {
+		t.Errorf("expected 3 calls, got %d", calls)
+	}
+}
+
+func TestRetryTransport_DoesNotRetryPost(t *testing.T) {
+	calls := 0
+	base := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		calls++
+		return newResponse(500, "error"), nil
+	})
+
+	req, _ := http.NewRequest("POST", "https://ctf.example.com/api/v1/challenges/attempt", strings.NewReader("{}"))
+	if _, err := newTestTransport(base, testOptions()).RoundTrip(req); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if calls != 1 {
+		t.Errorf("expected 1 call, got %d", calls)
+	}
+}
+
+func TestRetryTransport_RetriesPostThatNeverConnected(t *testing.T) {
+	calls := 0
+	var bodies []string
+	base := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		calls++
+		b, _ := io.ReadAll(req.Body)
+		bodies = append(bodies, string(b))
+		if calls == 1 {
+			return nil, &net.OpError{Op: "dial", Err: errors.New("connection refused")}
+		}
+		return newResponse(200, "ok"), nil
+	})
+
+	req, _ := http.NewRequest("POST", "https://ctf.example.com/login", strings.NewReader("name=a"))
+	if _, err := newTestTransport(base, testOptions()).RoundTrip(req); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if calls != 2 {
+		t.Errorf("expected 2 calls, got %d", calls)
+	}
+	for _, b := range bodies {
+		if b != "name=a" {
+			t.Errorf("expected body to be resent, got %q", b)
+		}
+	}
+}
+
+func TestRetryTransport_CircuitBreaker(t *testing.T) {
+	calls := 0
+	base := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		calls++
+		return nil, errors.New("connection reset")
+	})
+
+	opts := testOptions()
+	opts.Retries = 0
+	opts.BreakerThreshold = 2
+	opts.BreakerCooldown = time.Minute
+
+	now := time.Now()
+	transport := newTestTransport(base, opts)
+	transport.breaker.now = func() time.Time { return now }
+
+	for i := 0; i < 2; i++ {
+		req, _ := http.NewRequest("GET", "https://ctf.example.com/", nil)
+		if _, err := transport.RoundTrip(req); err == nil {
+			t.Fatalf("expected error, got nil")
+		}
+	}
+
+	req, _ := http.NewRequest("GET", "https://ctf.example.com/", nil)
+	if _, err := transport.RoundTrip(req); !errors.Is(err, ErrCircuitOpen) {
+		t.Errorf("expected %v, got %v", ErrCircuitOpen, err)
+	}
+	if calls != 2 {
+		t.Errorf("expected 2 calls while circuit is open, got %d", calls)
+	}
+
+	now = now.Add(2 * time.Minute)
+	req, _ = http.NewRequest("GET", "https://ctf.example.com/", nil)
+	if _, err := transport.RoundTrip(req); errors.Is(err, ErrCircuitOpen) {
+		t.Errorf("expected circuit to be half open after cooldown")
+	}
+	if calls != 3 {
+		t.Errorf("expected 3 calls after cooldown, got %d", calls)
+	}
+}
+
+func TestRetryTransport_Backoff(t *testing.T) {
+	transport := newRetryTransport(nil, DefaultOptions())
+
+	for attempt := 0; attempt < 10; attempt++ {
+		d := transport.backoff(attempt)
+		if d > transport.opts.MaxDelay {
+			t.Errorf("backoff(%d) = %v, exceeds max delay %v", attempt, d, transport.opts.MaxDelay)
+		}
+		if d < transport.opts.BaseDelay/2 {
+			t.Errorf("backoff(%d) = %v, below minimum delay", attempt, d)
+		}
+	}
+}
+
+func TestRetryTransport_TimeoutEndsWithHeaders(t *testing.T) {
+	opts := testOptions()
+	opts.Timeout = 20 * time.Millisecond
+	opts.Retries = 0
+
+	transport := newTestTransport(roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		// The body keeps arriving after the attempt timeout.
+		body, w := io.Pipe()
+		go func() {
+			for i := 0; i < 5; i++ {
+				time.Sleep(10 * time.Millisecond)
+				if _, err := w.Write([]byte("data")); err != nil {
+					return
+				}
+			}
+			w.Close()
+		}()
+		go func() {
+			<-req.Context().Done()
+			w.CloseWithError(req.Context().Err())
+		}()
+		return &http.Response{StatusCode: 200, Body: body, Header: make(http.Header)}, nil
+	}), opts)
+
+	req, _ := http.NewRequest("GET", "https://ctf.example.com/files/big.zip", nil)
+	resp, err := transport.RoundTrip(req)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("expected the download to finish, got %v", err)
+	}
+	if string(data) != strings.Repeat("data", 5) {
+		t.Errorf("unexpected body %q", data)
+	}
+}
+
+func TestRetryTransport_TimeoutWaitingForHeaders(t *testing.T) {
+	opts := testOptions()
+	opts.Timeout = 10 * time.Millisecond
+	opts.Retries = 0
+
+	transport := newTestTransport(roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		<-req.Context().Done()
+		return nil, req.Context().Err()
+	}), opts)
+
+	req, _ := http.NewRequest("GET", "https://ctf.example.com/api/v1/challenges", nil)
+	if _, err := transport.RoundTrip(req); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected a deadline error, got %v", err)
+	}
+}
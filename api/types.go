@@ -1,6 +1,10 @@
 package api
 
-import "context"
+import (
+	"context"
+	"net/http"
+	"time"
+)
 
 type CTFdAPI interface {
 	Login(ctx context.Context, user, password string) error
@@ -8,33 +12,107 @@ type CTFdAPI interface {
 	GetChallenge(ctx context.Context, id uint16) (*Challenge, error)
 	SubmitFlag(ctx context.Context, id int, flag string) (*AttemptResult, error)
 	GetScoreboard(ctx context.Context) ([]ScoreboardEntry, error)
+	GetHint(ctx context.Context, id int) (*Hint, error)
+	DownloadFile(ctx context.Context, fileURL string) ([]byte, error)
+	GetMe(ctx context.Context) (*User, error)
+	ImportSession(cookies []*http.Cookie, userAgent string) error
+	GetRegistrationForm(ctx context.Context) (*RegistrationForm, error)
+	Register(ctx context.Context, r Registration) error
+	TeamRequired(ctx context.Context) (bool, error)
+	CreateTeam(ctx context.Context, name, password string) error
+	JoinTeam(ctx context.Context, name, password string) error
+	CreateToken(ctx context.Context, description string, expiration time.Time) (*Token, error)
+	GetTokens(ctx context.Context) ([]Token, error)
+	DeleteToken(ctx context.Context, id int) error
+	StartOAuth(ctx context.Context) (string, error)
+	CompleteOAuth(ctx context.Context, redirectURL string) error
+	SessionCookies() []*http.Cookie
+	GetCTFStatus(ctx context.Context) (*CTFStatus, error)
+	GetMyTeam(ctx context.Context) (*Team, error)
+	GetNotifications(ctx context.Context) ([]Notification, error)
 }
 
 type ApiResponse[T any] struct {
-	Success bool `json:"success"`
-	Data    T    `json:"data"`
+	Success bool   `json:"success"`
+	Data    T      `json:"data"`
+	Message string `json:"message,omitempty"`
+	Errors  any    `json:"errors,omitempty"`
 }
 
 type Challenge struct {
-	Id             uint32   `json:"id"`
-	Type           string   `json:"type"`
-	Name           string   `json:"name"`
-	Description    string   `json:"description"`
-	Value          uint32   `json:"value"`
-	Solves         uint32   `json:"solves"`
-	SolvedByMe     bool     `json:"solved_by_me"`
-	Category       string   `json:"category"`
-	Files          []string `json:"files"`
-	ConnectionInfo string   `json:"connection_info"`
-	Tags           []string `json:"tags"`
-	Attempts       int      `json:"attempts"`
-	MaxAttempts    int      `json:"max_attempts"`
+	Id             uint32       `json:"id"`
+	Type           string       `json:"type"`
+	Name           string       `json:"name"`
+	Description    string       `json:"description"`
+	Value          uint32       `json:"value"`
+	Solves         uint32       `json:"solves"`
+	SolvedByMe     bool         `json:"solved_by_me"`
+	Category       string       `json:"category"`
+	Files          []string     `json:"files"`
+	ConnectionInfo string       `json:"connection_info"`
+	Connections    []Connection `json:"connections,omitempty"`
+	Tags           []string     `json:"tags"`
+	Attempts       int          `json:"attempts"`
+	MaxAttempts    int          `json:"max_attempts"`
 	Hints          []struct {
 		Id   int `json:"id"`
 		Cost int `json:"cost"`
 	} `json:"hints"`
 }
 
+type Hint struct {
+	Id        int    `json:"id"`
+	Type      string `json:"type"`
+	Challenge int    `json:"challenge"`
+	Content   string `json:"content"`
+	Cost      int    `json:"cost"`
+}
+
+type User struct {
+	Id          uint32 `json:"id"`
+	Name        string `json:"name"`
+	Email       string `json:"email"`
+	Affiliation string `json:"affiliation"`
+	TeamId      uint32 `json:"team_id"`
+	Place       string `json:"place"`
+	Score       int32  `json:"score"`
+}
+
+// Token is a personal access token. Value is only returned when the token
+// is created.
+type Token struct {
+	Id          int    `json:"id"`
+	Type        string `json:"type"`
+	UserId      int    `json:"user_id"`
+	Created     string `json:"created"`
+	Expiration  string `json:"expiration"`
+	Description string `json:"description"`
+	Value       string `json:"value,omitempty"`
+}
+
+type TokenRequest struct {
+	Description string `json:"description,omitempty"`
+	Expiration  string `json:"expiration,omitempty"`
+}
+
+// Notification is an announcement of the organizers.
+type Notification struct {
+	Id      uint32 `json:"id"`
+	Title   string `json:"title"`
+	Content string `json:"content"`
+	Date    string `json:"date"`
+}
+
+type Team struct {
+	Id          uint32 `json:"id"`
+	Name        string `json:"name"`
+	Affiliation string `json:"affiliation"`
+	CaptainId   uint32 `json:"captain_id"`
+	Members     []int  `json:"members"`
+	Place       string `json:"place"`
+	Score       int32  `json:"score"`
+}
+
 type ListChallenge struct {
 	Id         uint32 `json:"id"`
 	Type       string `json:"type"`
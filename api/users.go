@@ -0,0 +1,20 @@
+package api
+
+import (
+	"context"
+	"errors"
+)
+
+func (c *ApiClient) GetMe(ctx context.Context) (*User, error) {
+	resp, err := c.get(ctx, c.urlFor(usersMeApiURL))
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := decodeResponse[User](resp, errors.New(errFailedFetchingUser))
+	if err != nil {
+		return nil, err
+	}
+
+	return &user, nil
+}
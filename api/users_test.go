@@ -0,0 +1,55 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"testing"
+)
+
+func TestGetMe_Success(t *testing.T) {
+	responseBody := `{
+		"success": true,
+		"data": {
+			"id": 7,
+			"name": "player",
+			"email": "player@example.com",
+			"team_id": 3,
+			"place": "2nd",
+			"score": 1337
+		}
+	}`
+
+	mock := mockResponse(t, newResponse(200, responseBody))
+
+	base, _ := url.Parse("https://ctf.example.com")
+	api := &ApiClient{
+		client:  mock,
+		baseUrl: base,
+	}
+
+	user, err := api.GetMe(context.Background())
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if user.Name != "player" || user.TeamId != 3 || user.Score != 1337 {
+		t.Errorf("unexpected user %+v", user)
+	}
+}
+
+func TestGetMe_NotLoggedIn(t *testing.T) {
+	resp := newResponse(401, `{"message": "Unauthorized"}`)
+	resp.Header.Set("Content-Type", "application/json")
+	mock := mockResponse(t, resp)
+
+	base, _ := url.Parse("https://ctf.example.com")
+	api := &ApiClient{
+		client:  mock,
+		baseUrl: base,
+	}
+
+	_, err := api.GetMe(context.Background())
+	if !errors.Is(err, ErrSessionExpired) {
+		t.Errorf("expected %v, got %v", ErrSessionExpired, err)
+	}
+}
@@ -3,6 +3,7 @@ package api
 import (
 	"fmt"
 	"net/url"
+	"path"
 	"regexp"
 	"strings"
 
@@ -116,7 +117,22 @@ func parseBaseUrl(u string) (*url.URL, error) {
 	baseURL := &url.URL{
 		Scheme: ur.Scheme,
 		Host:   ur.Host,
+		Path:   normalizePathPrefix(ur.Path),
 	}
 
 	return baseURL, nil
 }
+
+// normalizePathPrefix cleans the path of a CTFd instance served below the
+// root of a host, e.g. "/ctf/" becomes "/ctf" and "/" becomes "".
+func normalizePathPrefix(p string) string {
+	if p == "" {
+		return ""
+	}
+
+	p = path.Clean("/" + p)
+	if p == "/" {
+		return ""
+	}
+	return p
+}
@@ -131,10 +131,25 @@ func TestParseBaseUrl(t *testing.T) {
 			expected: "http://192.168.1.100:3000",
 		},
 		{
-			name:     "URL with path (path should be stripped)",
+			name:     "URL with path prefix",
 			input:    "https://ctfd.example.com/some/path",
+			expected: "https://ctfd.example.com/some/path",
+		},
+		{
+			name:     "URL with path prefix and trailing slash",
+			input:    "https://ctfd.example.com/ctf/",
+			expected: "https://ctfd.example.com/ctf",
+		},
+		{
+			name:     "URL with root path",
+			input:    "https://ctfd.example.com/",
 			expected: "https://ctfd.example.com",
 		},
+		{
+			name:     "URL with unclean path prefix",
+			input:    "ctfd.example.com//ctf/./events/../",
+			expected: "https://ctfd.example.com/ctf",
+		},
 		{
 			name:     "URL with query parameters (should be stripped)",
 			input:    "https://ctfd.example.com?param=value",
@@ -148,7 +163,7 @@ func TestParseBaseUrl(t *testing.T) {
 		{
 			name:     "URL with path, query, and fragment",
 			input:    "https://ctfd.example.com/path?param=value#section",
-			expected: "https://ctfd.example.com",
+			expected: "https://ctfd.example.com/path",
 		},
 		{
 			name:     "URL with whitespace",
@@ -272,8 +287,8 @@ func TestParseBaseUrl(t *testing.T) {
 			if result.Host == "" {
 				t.Errorf("Result should have a host for input %q", tt.input)
 			}
-			if result.Path != "" {
-				t.Errorf("Base URL should not have a path, but got %q for input %q", result.Path, tt.input)
+			if strings.HasSuffix(result.Path, "/") {
+				t.Errorf("Base URL path should not end with a slash, but got %q for input %q", result.Path, tt.input)
 			}
 			if result.RawQuery != "" {
 				t.Errorf("Base URL should not have query parameters, but got %q for input %q", result.RawQuery, tt.input)
@@ -0,0 +1,337 @@
+package cache
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jonsth131/ctfd-cli/api"
+)
+
+var (
+	ErrOffline  = errors.New("not available in offline mode")
+	ErrNotFound = errors.New("no cached data available")
+)
+
+type entry[T any] struct {
+	Time time.Time `json:"time"`
+	Data T         `json:"data"`
+}
+
+type statusKey struct{}
+
+// Status reports whether a response was served from the cache. Attach one to
+// a context with WithStatus before calling the client.
+type Status struct {
+	Stale bool
+	Since time.Time
+	Err   error
+}
+
+func WithStatus(ctx context.Context) (context.Context, *Status) {
+	s := &Status{}
+	return context.WithValue(ctx, statusKey{}, s), s
+}
+
+func markStale(ctx context.Context, since time.Time, err error) {
+	if s, ok := ctx.Value(statusKey{}).(*Status); ok {
+		s.Stale = true
+		s.Since = since
+		s.Err = err
+	}
+}
+
+// Client wraps an api.CTFdAPI and stores the last successful responses on
+// disk. When a request fails, or the client is offline, the cached response
+// is returned instead.
+type Client struct {
+	api     api.CTFdAPI
+	dir     string
+	offline bool
+	mu      sync.Mutex
+}
+
+func New(client api.CTFdAPI, dir string, offline bool) *Client {
+	return &Client{api: client, dir: dir, offline: offline}
+}
+
+func (c *Client) Offline() bool {
+	return c.offline
+}
+
+func (c *Client) Login(ctx context.Context, user, password string) error {
+	if c.offline {
+		return nil
+	}
+	return c.api.Login(ctx, user, password)
+}
+
+func (c *Client) GetChallenges(ctx context.Context) ([]api.ListChallenge, error) {
+	return cached(ctx, c, "challenges.json", func() ([]api.ListChallenge, error) {
+		return c.api.GetChallenges(ctx)
+	})
+}
+
+func (c *Client) GetChallenge(ctx context.Context, id uint16) (*api.Challenge, error) {
+	return cached(ctx, c, fmt.Sprintf("challenge-%d.json", id), func() (*api.Challenge, error) {
+		return c.api.GetChallenge(ctx, id)
+	})
+}
+
+func (c *Client) SubmitFlag(ctx context.Context, id int, flag string) (*api.AttemptResult, error) {
+	if c.offline {
+		return nil, ErrOffline
+	}
+	return c.api.SubmitFlag(ctx, id, flag)
+}
+
+func (c *Client) GetScoreboard(ctx context.Context) ([]api.ScoreboardEntry, error) {
+	return cached(ctx, c, "scoreboard.json", func() ([]api.ScoreboardEntry, error) {
+		return c.api.GetScoreboard(ctx)
+	})
+}
+
+func (c *Client) GetHint(ctx context.Context, id int) (*api.Hint, error) {
+	return cached(ctx, c, fmt.Sprintf("hint-%d.json", id), func() (*api.Hint, error) {
+		return c.api.GetHint(ctx, id)
+	})
+}
+
+func (c *Client) GetMe(ctx context.Context) (*api.User, error) {
+	return cached(ctx, c, "me.json", func() (*api.User, error) {
+		return c.api.GetMe(ctx)
+	})
+}
+
+func (c *Client) GetMyTeam(ctx context.Context) (*api.Team, error) {
+	return cached(ctx, c, "team.json", func() (*api.Team, error) {
+		return c.api.GetMyTeam(ctx)
+	})
+}
+
+// GetNotifications is never cached, old notifications are of no use.
+func (c *Client) GetNotifications(ctx context.Context) ([]api.Notification, error) {
+	if c.offline {
+		return nil, ErrOffline
+	}
+	return c.api.GetNotifications(ctx)
+}
+
+func (c *Client) ImportSession(cookies []*http.Cookie, userAgent string) error {
+	return c.api.ImportSession(cookies, userAgent)
+}
+
+func (c *Client) GetRegistrationForm(ctx context.Context) (*api.RegistrationForm, error) {
+	if c.offline {
+		return nil, ErrOffline
+	}
+	return c.api.GetRegistrationForm(ctx)
+}
+
+func (c *Client) Register(ctx context.Context, r api.Registration) error {
+	if c.offline {
+		return ErrOffline
+	}
+	return c.api.Register(ctx, r)
+}
+
+// TeamRequired never asks for a team while offline, the cached data is shown
+// as is.
+func (c *Client) TeamRequired(ctx context.Context) (bool, error) {
+	if c.offline {
+		return false, nil
+	}
+	return c.api.TeamRequired(ctx)
+}
+
+func (c *Client) CreateTeam(ctx context.Context, name, password string) error {
+	if c.offline {
+		return ErrOffline
+	}
+	return c.api.CreateTeam(ctx, name, password)
+}
+
+func (c *Client) JoinTeam(ctx context.Context, name, password string) error {
+	if c.offline {
+		return ErrOffline
+	}
+	return c.api.JoinTeam(ctx, name, password)
+}
+
+func (c *Client) CreateToken(ctx context.Context, description string, expiration time.Time) (*api.Token, error) {
+	if c.offline {
+		return nil, ErrOffline
+	}
+	return c.api.CreateToken(ctx, description, expiration)
+}
+
+func (c *Client) GetTokens(ctx context.Context) ([]api.Token, error) {
+	if c.offline {
+		return nil, ErrOffline
+	}
+	return c.api.GetTokens(ctx)
+}
+
+func (c *Client) DeleteToken(ctx context.Context, id int) error {
+	if c.offline {
+		return ErrOffline
+	}
+	return c.api.DeleteToken(ctx, id)
+}
+
+func (c *Client) StartOAuth(ctx context.Context) (string, error) {
+	if c.offline {
+		return "", ErrOffline
+	}
+	return c.api.StartOAuth(ctx)
+}
+
+func (c *Client) CompleteOAuth(ctx context.Context, redirectURL string) error {
+	if c.offline {
+		return ErrOffline
+	}
+	return c.api.CompleteOAuth(ctx, redirectURL)
+}
+
+func (c *Client) GetCTFStatus(ctx context.Context) (*api.CTFStatus, error) {
+	if c.offline {
+		return nil, ErrOffline
+	}
+	return c.api.GetCTFStatus(ctx)
+}
+
+func (c *Client) SessionCookies() []*http.Cookie {
+	return c.api.SessionCookies()
+}
+
+func (c *Client) DownloadFile(ctx context.Context, fileURL string) ([]byte, error) {
+	name := filepath.Join("files", fileKey(fileURL))
+
+	if !c.offline {
+		data, err := c.api.DownloadFile(ctx, fileURL)
+		if err == nil {
+			if err := c.write(name, data); err != nil {
+				log.Default().Printf("Failed to cache file %s: %v", fileURL, err)
+			}
+			return data, nil
+		}
+		if !recoverable(err) {
+			return nil, err
+		}
+		return c.fallbackFile(ctx, name, err)
+	}
+
+	return c.fallbackFile(ctx, name, ErrOffline)
+}
+
+func (c *Client) fallbackFile(ctx context.Context, name string, reqErr error) ([]byte, error) {
+	p := filepath.Join(c.dir, name)
+	info, err := os.Stat(p)
+	if err != nil {
+		return nil, errors.Join(reqErr, ErrNotFound)
+	}
+	data, err := os.ReadFile(p)
+	if err != nil {
+		return nil, errors.Join(reqErr, err)
+	}
+	markStale(ctx, info.ModTime(), reqErr)
+	return data, nil
+}
+
+// fileKey strips the query string, which for CTFd contains a short lived
+// token, so the same file maps to the same cache entry between sessions.
+func fileKey(fileURL string) string {
+	p, _, _ := strings.Cut(fileURL, "?")
+	sum := sha256.Sum256([]byte(p))
+	return hex.EncodeToString(sum[:])
+}
+
+// recoverable reports whether a failed request should be answered from the
+// cache. Cancelled requests and expired sessions are returned to the caller
+// so it can react to them.
+func recoverable(err error) bool {
+	return !errors.Is(err, context.Canceled) && !errors.Is(err, api.ErrSessionExpired)
+}
+
+func cached[T any](ctx context.Context, c *Client, name string, fetch func() (T, error)) (T, error) {
+	var reqErr error = ErrOffline
+
+	if !c.offline {
+		data, err := fetch()
+		if err == nil {
+			if err := c.store(name, data); err != nil {
+				log.Default().Printf("Failed to cache %s: %v", name, err)
+			}
+			return data, nil
+		}
+		if !recoverable(err) {
+			return data, err
+		}
+		reqErr = err
+	}
+
+	e, err := load[T](c, name)
+	if err != nil {
+		var zero T
+		if c.offline {
+			return zero, errors.Join(reqErr, err)
+		}
+		return zero, reqErr
+	}
+
+	markStale(ctx, e.Time, reqErr)
+	return e.Data, nil
+}
+
+func (c *Client) store(name string, data any) error {
+	b, err := json.Marshal(entry[any]{Time: time.Now(), Data: data})
+	if err != nil {
+		return err
+	}
+	return c.write(name, b)
+}
+
+func (c *Client) write(name string, data []byte) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	p := filepath.Join(c.dir, name)
+	if err := os.MkdirAll(filepath.Dir(p), 0o700); err != nil {
+		return err
+	}
+
+	tmp := p + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, p)
+}
+
+func load[T any](c *Client, name string) (*entry[T], error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	data, err := os.ReadFile(filepath.Join(c.dir, name))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+
+	var e entry[T]
+	if err := json.Unmarshal(data, &e); err != nil {
+		return nil, err
+	}
+	return &e, nil
+}
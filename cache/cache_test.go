@@ -0,0 +1,191 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/jonsth131/ctfd-cli/api"
+)
+
+var errServer = errors.New("server unavailable")
+
+type fakeAPI struct {
+	err        error
+	challenges []api.ListChallenge
+	files      map[string][]byte
+}
+
+func (f *fakeAPI) Login(ctx context.Context, user, password string) error { return f.err }
+
+func (f *fakeAPI) GetChallenges(ctx context.Context) ([]api.ListChallenge, error) {
+	return f.challenges, f.err
+}
+
+func (f *fakeAPI) GetChallenge(ctx context.Context, id uint16) (*api.Challenge, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	return &api.Challenge{Id: uint32(id)}, nil
+}
+
+func (f *fakeAPI) SubmitFlag(ctx context.Context, id int, flag string) (*api.AttemptResult, error) {
+	return &api.AttemptResult{Status: "correct"}, f.err
+}
+
+func (f *fakeAPI) GetScoreboard(ctx context.Context) ([]api.ScoreboardEntry, error) {
+	return nil, f.err
+}
+
+func (f *fakeAPI) GetHint(ctx context.Context, id int) (*api.Hint, error) {
+	return &api.Hint{Id: id}, f.err
+}
+
+func (f *fakeAPI) GetMe(ctx context.Context) (*api.User, error) {
+	return &api.User{Name: "player"}, f.err
+}
+
+func (f *fakeAPI) ImportSession(cookies []*http.Cookie, userAgent string) error { return nil }
+
+func (f *fakeAPI) GetRegistrationForm(ctx context.Context) (*api.RegistrationForm, error) {
+	return &api.RegistrationForm{}, nil
+}
+
+func (f *fakeAPI) Register(ctx context.Context, r api.Registration) error { return nil }
+
+func (f *fakeAPI) TeamRequired(ctx context.Context) (bool, error) { return false, nil }
+
+func (f *fakeAPI) CreateTeam(ctx context.Context, name, password string) error { return nil }
+
+func (f *fakeAPI) JoinTeam(ctx context.Context, name, password string) error { return nil }
+
+func (f *fakeAPI) CreateToken(ctx context.Context, description string, expiration time.Time) (*api.Token, error) {
+	return &api.Token{}, nil
+}
+
+func (f *fakeAPI) GetTokens(ctx context.Context) ([]api.Token, error) { return nil, nil }
+
+func (f *fakeAPI) DeleteToken(ctx context.Context, id int) error { return nil }
+
+func (f *fakeAPI) StartOAuth(ctx context.Context) (string, error) { return "", nil }
+
+func (f *fakeAPI) CompleteOAuth(ctx context.Context, redirectURL string) error { return nil }
+
+func (f *fakeAPI) SessionCookies() []*http.Cookie { return nil }
+
+func (f *fakeAPI) GetNotifications(ctx context.Context) ([]api.Notification, error) {
+	return nil, f.err
+}
+
+func (f *fakeAPI) GetMyTeam(ctx context.Context) (*api.Team, error) {
+	return &api.Team{}, nil
+}
+
+func (f *fakeAPI) GetCTFStatus(ctx context.Context) (*api.CTFStatus, error) {
+	return &api.CTFStatus{}, nil
+}
+
+func (f *fakeAPI) DownloadFile(ctx context.Context, fileURL string) ([]byte, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	return f.files[fileURL], nil
+}
+
+func TestCachedChallengesServedWhenRequestFails(t *testing.T) {
+	fake := &fakeAPI{challenges: []api.ListChallenge{{Id: 1, Name: "warmup"}}}
+	c := New(fake, t.TempDir(), false)
+
+	ctx, status := WithStatus(context.Background())
+	if _, err := c.GetChallenges(ctx); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if status.Stale {
+		t.Errorf("expected fresh response")
+	}
+
+	fake.err = errServer
+	fake.challenges = nil
+
+	ctx, status = WithStatus(context.Background())
+	challenges, err := c.GetChallenges(ctx)
+	if err != nil {
+		t.Fatalf("expected cached response, got %v", err)
+	}
+	if len(challenges) != 1 || challenges[0].Name != "warmup" {
+		t.Errorf("unexpected cached challenges %+v", challenges)
+	}
+	if !status.Stale {
+		t.Errorf("expected stale response")
+	}
+	if !errors.Is(status.Err, errServer) {
+		t.Errorf("expected status error %v, got %v", errServer, status.Err)
+	}
+	if status.Since.IsZero() {
+		t.Errorf("expected stale since time to be set")
+	}
+}
+
+func TestRequestErrorReturnedWithoutCache(t *testing.T) {
+	c := New(&fakeAPI{err: errServer}, t.TempDir(), false)
+
+	_, err := c.GetChallenge(context.Background(), 1)
+	if !errors.Is(err, errServer) {
+		t.Errorf("expected %v, got %v", errServer, err)
+	}
+}
+
+func TestOfflineMode(t *testing.T) {
+	dir := t.TempDir()
+	fake := &fakeAPI{files: map[string][]byte{"/files/a/chall.zip?token=1": []byte("zip")}}
+
+	online := New(fake, dir, false)
+	if _, err := online.GetChallenge(context.Background(), 7); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if _, err := online.DownloadFile(context.Background(), "/files/a/chall.zip?token=1"); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	offline := New(fake, dir, true)
+
+	ctx, status := WithStatus(context.Background())
+	chall, err := offline.GetChallenge(ctx, 7)
+	if err != nil {
+		t.Fatalf("expected cached challenge, got %v", err)
+	}
+	if chall.Id != 7 || !status.Stale {
+		t.Errorf("expected stale challenge 7, got %+v (stale %t)", chall, status.Stale)
+	}
+
+	data, err := offline.DownloadFile(context.Background(), "/files/a/chall.zip?token=2")
+	if err != nil {
+		t.Fatalf("expected cached file, got %v", err)
+	}
+	if string(data) != "zip" {
+		t.Errorf("expected cached file contents, got %q", string(data))
+	}
+
+	if _, err := offline.GetScoreboard(context.Background()); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected %v, got %v", ErrNotFound, err)
+	}
+	if _, err := offline.SubmitFlag(context.Background(), 7, "flag"); !errors.Is(err, ErrOffline) {
+		t.Errorf("expected %v, got %v", ErrOffline, err)
+	}
+}
+
+func TestExpiredSessionNotServedFromCache(t *testing.T) {
+	fake := &fakeAPI{challenges: []api.ListChallenge{{Id: 1}}}
+	c := New(fake, t.TempDir(), false)
+
+	if _, err := c.GetChallenges(context.Background()); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	fake.err = api.ErrSessionExpired
+	if _, err := c.GetChallenges(context.Background()); !errors.Is(err, api.ErrSessionExpired) {
+		t.Errorf("expected %v, got %v", api.ErrSessionExpired, err)
+	}
+}
@@ -0,0 +1,74 @@
+package claims
+
+import (
+	"fmt"
+	"slices"
+	"time"
+)
+
+// Status is what a team member is doing on a challenge.
+type Status string
+
+const (
+	Working     Status = "working"
+	Stuck       Status = "stuck"
+	SolvedLocal Status = "solved-local"
+)
+
+// Statuses lists the statuses in the order they are cycled through.
+var Statuses = []Status{Working, Stuck, SolvedLocal}
+
+func (s Status) Valid() bool {
+	return slices.Contains(Statuses, s)
+}
+
+// Claim is a team member's status on a challenge. Every member has at most
+// one claim per challenge.
+type Claim struct {
+	Challenge int       `json:"challenge"`
+	User      string    `json:"user"`
+	Status    Status    `json:"status"`
+	Updated   time.Time `json:"updated"`
+}
+
+// Board holds the claims of a team. Version grows with every change, so
+// clients can wait for the next one.
+type Board struct {
+	Version int     `json:"version"`
+	Claims  []Claim `json:"claims"`
+}
+
+// Of returns the claims on a challenge.
+func (b Board) Of(challenge int) []Claim {
+	var claims []Claim
+	for _, c := range b.Claims {
+		if c.Challenge == challenge {
+			claims = append(claims, c)
+		}
+	}
+	return claims
+}
+
+// Find returns the claim of a user on a challenge.
+func (b Board) Find(challenge int, user string) (Claim, bool) {
+	for _, c := range b.Claims {
+		if c.Challenge == challenge && c.User == user {
+			return c, true
+		}
+	}
+	return Claim{}, false
+}
+
+// Next returns the status after s when cycling through the statuses, and
+// false after the last one, which clears the claim.
+func Next(s Status) (Status, bool) {
+	i := slices.Index(Statuses, s)
+	if i == len(Statuses)-1 {
+		return "", false
+	}
+	return Statuses[i+1], true
+}
+
+func (c Claim) String() string {
+	return fmt.Sprintf("%s: %s", c.User, c.Status)
+}
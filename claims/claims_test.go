@@ -0,0 +1,187 @@
+package claims
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newTestServer(t *testing.T, token, file string) *httptest.Server {
+	s, err := NewServer(token, file)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	ts := httptest.NewServer(s.Handler())
+	t.Cleanup(ts.Close)
+	return ts
+}
+
+func TestSetAndClear(t *testing.T) {
+	ts := newTestServer(t, "", "")
+	c := NewClient(ts.URL, "")
+	ctx := context.Background()
+
+	if _, err := c.Set(ctx, 3, "alice", Working); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := c.Set(ctx, 3, "bob", Stuck); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	board, err := c.Set(ctx, 3, "alice", SolvedLocal)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if board.Version != 3 {
+		t.Errorf("expected version 3, got %d", board.Version)
+	}
+	if claims := board.Of(3); len(claims) != 2 {
+		t.Fatalf("expected 2 claims, got %v", claims)
+	}
+	if claim, ok := board.Find(3, "alice"); !ok || claim.Status != SolvedLocal {
+		t.Errorf("expected alice to have solved locally, got %v", claim)
+	}
+
+	if _, err := c.Clear(ctx, 3, "alice"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	board, err = c.Board(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, ok := board.Find(3, "alice"); ok {
+		t.Error("expected the claim of alice to be cleared")
+	}
+	if _, ok := board.Find(3, "bob"); !ok {
+		t.Error("expected the claim of bob to be kept")
+	}
+}
+
+func TestInvalidStatus(t *testing.T) {
+	ts := newTestServer(t, "", "")
+	c := NewClient(ts.URL, "")
+
+	_, err := c.Set(context.Background(), 1, "alice", "bored")
+	if err == nil || !strings.Contains(err.Error(), "invalid status") {
+		t.Errorf("expected invalid status error, got %v", err)
+	}
+}
+
+func TestToken(t *testing.T) {
+	ts := newTestServer(t, "secret", "")
+
+	if _, err := NewClient(ts.URL, "wrong").Board(context.Background()); err == nil || !strings.Contains(err.Error(), "401") {
+		t.Errorf("expected unauthorized error, got %v", err)
+	}
+	if _, err := NewClient(ts.URL, "secret").Board(context.Background()); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestWait(t *testing.T) {
+	ts := newTestServer(t, "", "")
+	c := NewClient(ts.URL, "")
+	ctx := context.Background()
+
+	done := make(chan Board)
+	go func() {
+		board, err := c.Wait(ctx, 0)
+		if err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+		done <- board
+	}()
+
+	select {
+	case <-done:
+		t.Fatal("expected wait to block until a change")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	if _, err := c.Set(ctx, 7, "alice", Working); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	select {
+	case board := <-done:
+		if board.Version != 1 || len(board.Claims) != 1 {
+			t.Errorf("unexpected board %+v", board)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected wait to return after a change")
+	}
+
+	// An outdated version returns right away.
+	board, err := c.Wait(ctx, 0)
+	if err != nil || board.Version != 1 {
+		t.Errorf("expected version 1, got %d, %v", board.Version, err)
+	}
+}
+
+func TestPersistence(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "claims.json")
+	ts := newTestServer(t, "", file)
+	if _, err := NewClient(ts.URL, "").Set(context.Background(), 2, "alice", Stuck); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	ts = newTestServer(t, "", file)
+	board, err := NewClient(ts.URL, "").Board(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if claim, ok := board.Find(2, "alice"); !ok || claim.Status != Stuck || board.Version != 1 {
+		t.Errorf("expected the board to be loaded, got %+v", board)
+	}
+}
+
+func TestNext(t *testing.T) {
+	var got []Status
+	status, ok := Next("")
+	for ok {
+		got = append(got, status)
+		status, ok = Next(status)
+	}
+	if len(got) != 3 || got[0] != Working || got[1] != Stuck || got[2] != SolvedLocal {
+		t.Errorf("unexpected cycle %v", got)
+	}
+}
+
+func TestLimits(t *testing.T) {
+	s, err := NewServer("", "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for i := 0; i < MaxClaims; i++ {
+		s.board.Claims = append(s.board.Claims, Claim{Challenge: i, User: "alice", Status: Working})
+	}
+	ts := httptest.NewServer(s.Handler())
+	defer ts.Close()
+	c := NewClient(ts.URL, "")
+	ctx := context.Background()
+
+	if _, err := c.Set(ctx, 0, strings.Repeat("a", MaxUserLen+1), Working); err == nil || !strings.Contains(err.Error(), "user longer") {
+		t.Errorf("expected user length error, got %v", err)
+	}
+	if _, err := c.Set(ctx, MaxClaims, "alice", Working); err == nil || !strings.Contains(err.Error(), "full") {
+		t.Errorf("expected full board error, got %v", err)
+	}
+	// Existing claims can still be changed.
+	if _, err := c.Set(ctx, 0, "alice", Stuck); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	req, _ := http.NewRequest(http.MethodPut, ts.URL+"/claims/1", strings.NewReader(`{"user": "`+strings.Repeat("a", 8<<10)+`"}`))
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("expected a large body to be rejected, got %s", resp.Status)
+	}
+}
@@ -0,0 +1,93 @@
+package claims
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Client talks to a claim board served by Server.
+type Client struct {
+	url   string
+	token string
+	http  *http.Client
+}
+
+func NewClient(serverURL, token string) *Client {
+	return &Client{
+		url:   strings.TrimRight(serverURL, "/"),
+		token: token,
+		// Waiting for changes takes up to MaxWait.
+		http: &http.Client{Timeout: MaxWait + 10*time.Second},
+	}
+}
+
+// Board returns the current board.
+func (c *Client) Board(ctx context.Context) (Board, error) {
+	var board Board
+	err := c.do(ctx, http.MethodGet, "/claims", nil, &board)
+	return board, err
+}
+
+// Wait returns the board once its version is after version, or the current
+// board after MaxWait.
+func (c *Client) Wait(ctx context.Context, version int) (Board, error) {
+	var board Board
+	err := c.do(ctx, http.MethodGet, "/claims?version="+strconv.Itoa(version), nil, &board)
+	return board, err
+}
+
+// Set sets the claim of a user on a challenge.
+func (c *Client) Set(ctx context.Context, challenge int, user string, status Status) (Board, error) {
+	var board Board
+	err := c.do(ctx, http.MethodPut, fmt.Sprintf("/claims/%d", challenge), Claim{User: user, Status: status}, &board)
+	return board, err
+}
+
+// Clear removes the claim of a user on a challenge.
+func (c *Client) Clear(ctx context.Context, challenge int, user string) (Board, error) {
+	var board Board
+	err := c.do(ctx, http.MethodDelete, fmt.Sprintf("/claims/%d?user=%s", challenge, url.QueryEscape(user)), nil, &board)
+	return board, err
+}
+
+func (c *Client) do(ctx context.Context, method, path string, body, out any) error {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.url+path, reader)
+	if err != nil {
+		return err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return fmt.Errorf("claim server: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("claim server: %s: %s", resp.Status, strings.TrimSpace(string(msg)))
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
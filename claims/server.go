@@ -0,0 +1,254 @@
+package claims
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// MaxWait bounds how long a request for the board waits for a change.
+const MaxWait = 30 * time.Second
+
+// Limits on what clients can store, so a board can't be grown without bound.
+const (
+	maxBodySize = 4 << 10
+	MaxUserLen  = 64
+	MaxClaims   = 2000
+)
+
+// Server keeps the claim board of a team in memory and saves it to a file
+// after every change if one is given.
+type Server struct {
+	token string
+	file  string
+
+	mu    sync.Mutex
+	board Board
+	// changed is closed and replaced on every change, waking up waiting
+	// requests.
+	changed chan struct{}
+}
+
+// NewServer creates a server that requires token as a bearer token, unless
+// it is empty, and loads the board from file if it exists.
+func NewServer(token, file string) (*Server, error) {
+	s := &Server{token: token, file: file, changed: make(chan struct{})}
+	if file == "" {
+		return s, nil
+	}
+
+	data, err := os.ReadFile(file)
+	if errors.Is(err, os.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read claims: %w", err)
+	}
+	if err := json.Unmarshal(data, &s.board); err != nil {
+		return nil, fmt.Errorf("failed to parse claims %s: %w", file, err)
+	}
+	return s, nil
+}
+
+var errBoardFull = fmt.Errorf("the board is full, at most %d claims are kept", MaxClaims)
+
+// Handler serves the board:
+//
+//	GET    /claims?version=N   the board, waiting for a version after N
+//	PUT    /claims/{id}        set the claim {"user", "status"} on a challenge
+//	DELETE /claims/{id}?user=U remove the claim of a user on a challenge
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /claims", s.getBoard)
+	mux.HandleFunc("PUT /claims/{id}", s.putClaim)
+	mux.HandleFunc("DELETE /claims/{id}", s.deleteClaim)
+	return s.authorize(mux)
+}
+
+func (s *Server) authorize(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if s.token != "" {
+			want := "Bearer " + s.token
+			if subtle.ConstantTimeCompare([]byte(r.Header.Get("Authorization")), []byte(want)) != 1 {
+				http.Error(w, "invalid token", http.StatusUnauthorized)
+				return
+			}
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (s *Server) getBoard(w http.ResponseWriter, r *http.Request) {
+	if v := r.URL.Query().Get("version"); v != "" {
+		version, err := strconv.Atoi(v)
+		if err != nil {
+			http.Error(w, "invalid version", http.StatusBadRequest)
+			return
+		}
+		s.wait(r, version)
+	}
+
+	s.mu.Lock()
+	board := s.board
+	s.mu.Unlock()
+	writeJSON(w, board)
+}
+
+// wait blocks until the version of the board is after version, MaxWait has
+// passed or the request is cancelled.
+func (s *Server) wait(r *http.Request, version int) {
+	timer := time.NewTimer(MaxWait)
+	defer timer.Stop()
+	for {
+		s.mu.Lock()
+		current, changed := s.board.Version, s.changed
+		s.mu.Unlock()
+		if current != version {
+			return
+		}
+
+		select {
+		case <-changed:
+		case <-timer.C:
+			return
+		case <-r.Context().Done():
+			return
+		}
+	}
+}
+
+func (s *Server) putClaim(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, "invalid challenge id", http.StatusBadRequest)
+		return
+	}
+
+	var claim Claim
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxBodySize)).Decode(&claim); err != nil {
+		http.Error(w, "invalid claim", http.StatusBadRequest)
+		return
+	}
+	if claim.User == "" {
+		http.Error(w, "missing user", http.StatusBadRequest)
+		return
+	}
+	if len(claim.User) > MaxUserLen {
+		http.Error(w, fmt.Sprintf("user longer than %d bytes", MaxUserLen), http.StatusBadRequest)
+		return
+	}
+	if !claim.Status.Valid() {
+		http.Error(w, fmt.Sprintf("invalid status %q", claim.Status), http.StatusBadRequest)
+		return
+	}
+	claim.Challenge = id
+	claim.Updated = time.Now()
+
+	board, err := s.update(func(claims []Claim) ([]Claim, error) {
+		for i, c := range claims {
+			if c.Challenge == id && c.User == claim.User {
+				claims[i] = claim
+				return claims, nil
+			}
+		}
+		if len(claims) >= MaxClaims {
+			return nil, errBoardFull
+		}
+		return append(claims, claim), nil
+	})
+	if errors.Is(err, errBoardFull) {
+		http.Error(w, err.Error(), http.StatusInsufficientStorage)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, board)
+}
+
+func (s *Server) deleteClaim(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		http.Error(w, "invalid challenge id", http.StatusBadRequest)
+		return
+	}
+	user := r.URL.Query().Get("user")
+	if user == "" {
+		http.Error(w, "missing user", http.StatusBadRequest)
+		return
+	}
+
+	board, err := s.update(func(claims []Claim) ([]Claim, error) {
+		var kept []Claim
+		for _, c := range claims {
+			if c.Challenge != id || c.User != user {
+				kept = append(kept, c)
+			}
+		}
+		return kept, nil
+	})
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, board)
+}
+
+// update changes the claims, saves the board and wakes up the requests
+// waiting for it.
+func (s *Server) update(fn func(claims []Claim) ([]Claim, error)) (Board, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	claims, err := fn(append([]Claim(nil), s.board.Claims...))
+	if err != nil {
+		return Board{}, err
+	}
+	board := Board{Version: s.board.Version + 1, Claims: claims}
+	if err := s.save(board); err != nil {
+		return Board{}, err
+	}
+
+	s.board = board
+	close(s.changed)
+	s.changed = make(chan struct{})
+	return board, nil
+}
+
+func (s *Server) save(board Board) error {
+	if s.file == "" {
+		return nil
+	}
+
+	data, err := json.Marshal(board)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(s.file), 0o700); err != nil {
+		return fmt.Errorf("failed to save claims: %w", err)
+	}
+	tmp := s.file + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return fmt.Errorf("failed to save claims: %w", err)
+	}
+	if err := os.Rename(tmp, s.file); err != nil {
+		return fmt.Errorf("failed to save claims: %w", err)
+	}
+	return nil
+}
+
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		log.Default().Printf("Failed to write response: %v", err)
+	}
+}
@@ -0,0 +1,498 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/jonsth131/ctfd-cli/api"
+	"github.com/jonsth131/ctfd-cli/cache"
+	"github.com/jonsth131/ctfd-cli/claims"
+	"github.com/jonsth131/ctfd-cli/config"
+	"github.com/jonsth131/ctfd-cli/notes"
+)
+
+type commandEnv struct {
+	baseUrl    string
+	user       string
+	opts       api.Options
+	cfg        *config.Config
+	configPath string
+	profile    string
+}
+
+func runCommand(env commandEnv, args []string) error {
+	// Notes are local and need neither the server nor a login.
+	if args[0] == "notes" {
+		return notesCommand(env, args[1:])
+	}
+
+	client, err := api.NewApiClientWithOptions(env.baseUrl, env.opts)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*env.opts.Budget())
+	defer cancel()
+
+	switch args[0] {
+	case "import-session":
+		return importSessionCommand(ctx, env, client, args[1:])
+	case "oauth-login":
+		return oauthLoginCommand(env, client, args[1:])
+	}
+
+	if err := env.login(ctx, client); err != nil {
+		return err
+	}
+
+	switch args[0] {
+	case "challenges":
+		challenges, err := client.GetChallenges(ctx)
+		if err != nil {
+			return err
+		}
+		return printJSON(challenges)
+	case "challenge":
+		if len(args) != 2 {
+			return fmt.Errorf("usage: challenge <id>")
+		}
+		id, err := strconv.ParseUint(args[1], 10, 16)
+		if err != nil {
+			return fmt.Errorf("invalid challenge id %q", args[1])
+		}
+		challenge, err := client.GetChallenge(ctx, uint16(id))
+		if err != nil {
+			return err
+		}
+		return printJSON(challenge)
+	case "token":
+		return tokenCommand(ctx, env, client, args[1:])
+	default:
+		return fmt.Errorf("unknown command %q", args[0])
+	}
+}
+
+// login authenticates the client with the profile's token or imported
+// session, or with a username and password when one is given.
+func (env commandEnv) login(ctx context.Context, client *api.ApiClient) error {
+	profile := env.cfg.Profiles[env.profile]
+
+	user := env.user
+	if user == "" && profile.Token != "" {
+		client.SetToken(profile.Token)
+		return nil
+	}
+	if user == "" && profile.Session != "" {
+		cookies, err := api.ParseCookies(profile.Session)
+		if err != nil {
+			return err
+		}
+		return client.ImportSession(cookies, profile.Network.UserAgent)
+	}
+
+	if user == "" {
+		user = profile.User
+	}
+	if user == "" {
+		return nil
+	}
+
+	password := os.Getenv("CTFD_PASSWORD")
+	if password == "" {
+		password = profile.Password
+	}
+	if err := client.Login(ctx, user, password); err != nil {
+		return fmt.Errorf("failed to login: %w", err)
+	}
+
+	if profile.AutoToken {
+		return env.switchToToken(ctx, client, tokenDescription, time.Time{})
+	}
+	return nil
+}
+
+// switchToToken creates a token and stores it in the profile instead of the
+// password and session.
+func (env commandEnv) switchToToken(ctx context.Context, client *api.ApiClient, description string, expiration time.Time) error {
+	profile := env.cfg.Profiles[env.profile]
+	if profile.Network.BasicAuthUser != "" {
+		return config.ErrTokenWithBasicAuth
+	}
+
+	token, err := client.CreateToken(ctx, description, expiration)
+	if err != nil {
+		return err
+	}
+
+	profile.URL = env.baseUrl
+	profile.Token = token.Value
+	profile.Password = ""
+	profile.Session = ""
+	if err := env.saveProfile(profile); err != nil {
+		return err
+	}
+
+	client.SetToken(token.Value)
+	fmt.Fprintf(os.Stderr, "Saved token %d to profile %q, the password is no longer stored\n", token.Id, env.profile)
+	return nil
+}
+
+func (env commandEnv) saveProfile(profile config.Profile) error {
+	if env.cfg.Profiles == nil {
+		env.cfg.Profiles = map[string]config.Profile{}
+	}
+	env.cfg.Profiles[env.profile] = profile
+	return env.cfg.Save(env.configPath)
+}
+
+func importSessionCommand(ctx context.Context, env commandEnv, client *api.ApiClient, args []string) error {
+	fs := flag.NewFlagSet("import-session", flag.ContinueOnError)
+	cookiesFile := fs.String("cookies-file", "", "Netscape cookies.txt file exported from the browser")
+	cookieHeader := fs.String("cookie", "", "Raw Cookie header copied from the browser")
+	session := fs.String("session", "", "Value of the session cookie")
+	clearance := fs.String("cf-clearance", "", "Value of the cf_clearance cookie")
+	userAgent := fs.String("user-agent", "", "User-Agent of the browser the cookies are from")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	var input string
+	switch {
+	case *cookiesFile != "":
+		data, err := os.ReadFile(*cookiesFile)
+		if err != nil {
+			return err
+		}
+		input = string(data)
+	case *cookieHeader != "":
+		input = *cookieHeader
+	case *session != "":
+		parts := []string{"session=" + *session}
+		if *clearance != "" {
+			parts = append(parts, "cf_clearance="+*clearance)
+		}
+		input = strings.Join(parts, "; ")
+	default:
+		return fmt.Errorf("usage: import-session (-cookies-file <file> | -cookie <header> | -session <value> [-cf-clearance <value>]) [-user-agent <ua>]")
+	}
+
+	cookies, err := api.ParseCookies(input)
+	if err != nil {
+		return err
+	}
+
+	profile := env.cfg.Profiles[env.profile]
+	if *userAgent == "" {
+		*userAgent = profile.Network.UserAgent
+	}
+
+	if err := client.ImportSession(cookies, *userAgent); err != nil {
+		return err
+	}
+
+	user, err := client.GetMe(ctx)
+	if err != nil {
+		return fmt.Errorf("imported session doesn't work: %w", err)
+	}
+
+	profile.URL = env.baseUrl
+	profile.Session = api.CookieHeader(client.SessionCookies())
+	profile.Network.UserAgent = *userAgent
+	if err := env.saveProfile(profile); err != nil {
+		return err
+	}
+
+	fmt.Printf("Imported session for %s into profile %q\n", user.Name, env.profile)
+	return nil
+}
+
+// notesCommand prints the notes on a challenge, or exports the notes on all
+// challenges as one markdown document, named after the cached challenges.
+func notesCommand(env commandEnv, args []string) error {
+	fs := flag.NewFlagSet("notes", flag.ContinueOnError)
+	output := fs.String("o", "", "Write to a file instead of stdout")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() > 1 {
+		return fmt.Errorf("usage: notes [-o <file>] [<id>]")
+	}
+
+	dir, err := config.NotesDir(env.profile)
+	if err != nil {
+		return err
+	}
+	store := notes.New(dir)
+
+	w := os.Stdout
+	if *output != "" {
+		f, err := os.Create(*output)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		w = f
+	}
+
+	if fs.NArg() == 1 {
+		id, err := strconv.Atoi(fs.Arg(0))
+		if err != nil {
+			return fmt.Errorf("invalid challenge id %q", fs.Arg(0))
+		}
+		text, err := store.Get(id)
+		if err != nil {
+			return err
+		}
+		_, err = io.WriteString(w, text)
+		return err
+	}
+
+	ids, err := store.List()
+	if err != nil {
+		return err
+	}
+
+	names := map[int]string{}
+	if cacheDir, err := config.CacheDir(env.profile); err == nil {
+		challenges, _ := cache.New(nil, cacheDir, true).GetChallenges(context.Background())
+		for _, c := range challenges {
+			names[int(c.Id)] = fmt.Sprintf("%s (%s)", c.Name, c.Category)
+		}
+	}
+
+	for i, id := range ids {
+		text, err := store.Get(id)
+		if err != nil {
+			return err
+		}
+		name, ok := names[id]
+		if !ok {
+			name = fmt.Sprintf("Challenge %d", id)
+		}
+		if i > 0 {
+			fmt.Fprintln(w)
+		}
+		fmt.Fprintf(w, "# %s\n\n%s\n", name, strings.TrimSpace(text))
+	}
+	return nil
+}
+
+// teamServerCommand serves a claim board for a team.
+func teamServerCommand(args []string) error {
+	fs := flag.NewFlagSet("team-server", flag.ContinueOnError)
+	listen := fs.String("listen", "127.0.0.1:8080", "Address to listen on")
+	token := fs.String("token", os.Getenv("CTFD_CLAIMS_TOKEN"), "Token the team must send, defaults to CTFD_CLAIMS_TOKEN")
+	file := fs.String("file", "", "File to keep the claims in across restarts")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *token == "" && !isLoopback(*listen) {
+		return fmt.Errorf("a token is required to listen on %s, set -token or CTFD_CLAIMS_TOKEN", *listen)
+	}
+
+	s, err := claims.NewServer(*token, *file)
+	if err != nil {
+		return err
+	}
+	server := &http.Server{
+		Addr:              *listen,
+		Handler:           s.Handler(),
+		ReadHeaderTimeout: 10 * time.Second,
+		ReadTimeout:       30 * time.Second,
+		// Requests for the board wait up to MaxWait for a change.
+		WriteTimeout: claims.MaxWait + 30*time.Second,
+		IdleTimeout:  2 * time.Minute,
+	}
+	fmt.Fprintf(os.Stderr, "Serving claims on %s\n", *listen)
+	return server.ListenAndServe()
+}
+
+// isLoopback reports whether addr only listens on the local machine.
+func isLoopback(addr string) bool {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return false
+	}
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+const tokenDescription = "ctfd-cli"
+
+func tokenCommand(ctx context.Context, env commandEnv, client *api.ApiClient, args []string) error {
+	const usage = "usage: token (create [-description <text>] [-expires <YYYY-MM-DD>] [-save] | list | revoke <id>)"
+	if len(args) == 0 {
+		return fmt.Errorf(usage)
+	}
+
+	switch args[0] {
+	case "create":
+		fs := flag.NewFlagSet("token create", flag.ContinueOnError)
+		description := fs.String("description", tokenDescription, "Description shown in CTFd's settings")
+		expires := fs.String("expires", "", "Expiration date, CTFd defaults to 30 days")
+		save := fs.Bool("save", false, "Store the token in the profile and forget the password")
+		if err := fs.Parse(args[1:]); err != nil {
+			return err
+		}
+
+		var expiration time.Time
+		if *expires != "" {
+			t, err := time.Parse(time.DateOnly, *expires)
+			if err != nil {
+				return fmt.Errorf("invalid expiration date %q", *expires)
+			}
+			expiration = t
+		}
+
+		if *save {
+			return env.switchToToken(ctx, client, *description, expiration)
+		}
+
+		token, err := client.CreateToken(ctx, *description, expiration)
+		if err != nil {
+			return err
+		}
+		fmt.Println(token.Value)
+		return nil
+	case "list":
+		tokens, err := client.GetTokens(ctx)
+		if err != nil {
+			return err
+		}
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "ID\tDESCRIPTION\tCREATED\tEXPIRES")
+		for _, t := range tokens {
+			fmt.Fprintf(w, "%d\t%s\t%s\t%s\n", t.Id, t.Description, t.Created, t.Expiration)
+		}
+		return w.Flush()
+	case "revoke":
+		if len(args) != 2 {
+			return fmt.Errorf("usage: token revoke <id>")
+		}
+		id, err := strconv.Atoi(args[1])
+		if err != nil {
+			return fmt.Errorf("invalid token id %q", args[1])
+		}
+		if err := client.DeleteToken(ctx, id); err != nil {
+			return err
+		}
+		fmt.Printf("Revoked token %d\n", id)
+		return nil
+	default:
+		return fmt.Errorf(usage)
+	}
+}
+
+// oauthLoginCommand logs in through the CTF's OAuth provider. The URL the
+// browser ends up on is pasted, or with -callback caught by a local callback
+// for providers that accept loopback redirect URIs.
+func oauthLoginCommand(env commandEnv, client *api.ApiClient, args []string) error {
+	fs := flag.NewFlagSet("oauth-login", flag.ContinueOnError)
+	listen := fs.String("listen", "127.0.0.1:0", "Address of the local callback")
+	useCallback := fs.Bool("callback", false, "Redirect to a local callback instead of the CTF, if the provider allows it")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), env.opts.Budget())
+	defer cancel()
+
+	authURL, err := client.StartOAuth(ctx)
+	if err != nil {
+		return err
+	}
+
+	redirects := make(chan string, 2)
+	errs := make(chan error, 2)
+
+	if *useCallback {
+		callback, err := api.ListenOAuthCallback(*listen)
+		if err != nil {
+			return err
+		}
+		defer callback.Close()
+
+		authURL, err = callback.AuthorizationURL(authURL)
+		if err != nil {
+			return err
+		}
+
+		go func() {
+			u, err := callback.Wait(context.Background())
+			if err != nil {
+				errs <- err
+				return
+			}
+			redirects <- u
+		}()
+	}
+
+	go func() {
+		line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+		if strings.TrimSpace(line) != "" {
+			redirects <- strings.TrimSpace(line)
+			return
+		}
+		if err == nil {
+			err = errors.New("no redirect URL given")
+		}
+		errs <- err
+	}()
+
+	fmt.Fprintf(os.Stderr, "Open this URL in a browser and login:\n\n  %s\n\n", authURL)
+	if *useCallback {
+		fmt.Fprintln(os.Stderr, "Waiting for the callback. If the browser doesn't return here, paste the URL it ended up on:")
+	} else {
+		fmt.Fprintln(os.Stderr, "Then paste the URL of the CTF page the browser ended up on:")
+	}
+
+	var redirectURL string
+	select {
+	case redirectURL = <-redirects:
+	case err := <-errs:
+		return err
+	}
+
+	ctx, cancel = context.WithTimeout(context.Background(), 2*env.opts.Budget())
+	defer cancel()
+
+	if err := client.CompleteOAuth(ctx, redirectURL); err != nil {
+		return err
+	}
+
+	user, err := client.GetMe(ctx)
+	if err != nil {
+		return fmt.Errorf("OAuth login didn't create a session: %w", err)
+	}
+
+	profile := env.cfg.Profiles[env.profile]
+	profile.URL = env.baseUrl
+	profile.Session = api.CookieHeader(client.SessionCookies())
+	if err := env.saveProfile(profile); err != nil {
+		return err
+	}
+
+	fmt.Printf("Logged in as %s, session saved to profile %q\n", user.Name, env.profile)
+	return nil
+}
+
+func printJSON(v any) error {
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
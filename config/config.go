@@ -0,0 +1,246 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/jonsth131/ctfd-cli/api"
+)
+
+const (
+	appName    = "ctfd-cli"
+	configFile = "config.json"
+)
+
+type Profile struct {
+	URL string `json:"url"`
+	// User and Password are used to prefill the login form and to login
+	// again when the session expires.
+	User     string `json:"user,omitempty"`
+	Password string `json:"password,omitempty"`
+	// Session holds cookies imported from a browser as a Cookie header. It
+	// is used instead of logging in with User and Password.
+	Session string `json:"session,omitempty"`
+	// Token is a personal access token. It takes precedence over Session
+	// and Password.
+	Token string `json:"token,omitempty"`
+	// AutoToken creates a token after the next password login, stores it
+	// in Token and forgets Password.
+	AutoToken bool               `json:"auto_token,omitempty"`
+	Network   api.NetworkOptions `json:"network,omitempty"`
+	// Claims is the team's claim board, see ctfd-cli team-server.
+	Claims Claims `json:"claims,omitempty"`
+}
+
+// ErrTokenWithBasicAuth is returned for profiles with both a token and the
+// basic auth credentials of a gateway, which are both sent in the
+// Authorization header.
+var ErrTokenWithBasicAuth = errors.New("a token can't be used together with basic_auth_user, both are sent in the Authorization header")
+
+// Validate reports settings of the profile that can't be used together.
+func (p Profile) Validate() error {
+	if (p.Token != "" || p.AutoToken) && p.Network.BasicAuthUser != "" {
+		return ErrTokenWithBasicAuth
+	}
+	return nil
+}
+
+// Claims connects to a claim board where team members share which
+// challenges they are working on.
+type Claims struct {
+	URL   string `json:"url,omitempty"`
+	Token string `json:"token,omitempty"`
+	// User is the name shown to the team, by default the user of the
+	// profile or the name of the logged in user.
+	User string `json:"user,omitempty"`
+}
+
+type Config struct {
+	// Terminal is a text/template used to run a command in a new terminal
+	// window. The command is available as {{.Command}} and can be shell
+	// quoted with {{quote .Command}}.
+	Terminal string `json:"terminal,omitempty"`
+	// DownloadDir is where challenge files are saved, one directory per
+	// challenge.
+	DownloadDir string `json:"download_dir,omitempty"`
+	// Keys customizes the key bindings of the TUI.
+	Keys Keys `json:"keys,omitempty"`
+	// Theme is the name of a built-in theme, "dark", "light" or
+	// "high-contrast", or of one of Themes. "auto", the default, picks dark
+	// or light from the terminal background.
+	Theme    string             `json:"theme,omitempty"`
+	Themes   map[string]Theme   `json:"themes,omitempty"`
+	Profiles map[string]Profile `json:"profiles,omitempty"`
+	// Hooks run shell commands when something happens in the CTF while the
+	// TUI is open.
+	Hooks []Hook `json:"hooks,omitempty"`
+}
+
+// Hook runs Command on Event, one of "solve", "new_challenge",
+// "notification" or "rank_change". The details of the event are passed in
+// CTFD_* environment variables.
+type Hook struct {
+	Event   string `json:"event"`
+	Command string `json:"command"`
+}
+
+// Theme holds the colors of the TUI as ANSI color numbers or hex codes.
+// Colors left empty are taken from Base, a built-in theme that defaults to
+// dark.
+type Theme struct {
+	Base string `json:"base,omitempty"`
+	// Muted is used for help and other secondary text.
+	Muted string `json:"muted,omitempty"`
+	// Border is used for borders and unfocused inputs.
+	Border string `json:"border,omitempty"`
+	// Accent is used for focused inputs, spinners and progress bars.
+	Accent     string `json:"accent,omitempty"`
+	Alert      string `json:"alert,omitempty"`
+	Error      string `json:"error,omitempty"`
+	SelectedFg string `json:"selected_fg,omitempty"`
+	SelectedBg string `json:"selected_bg,omitempty"`
+	StatusFg   string `json:"status_fg,omitempty"`
+	StatusBg   string `json:"status_bg,omitempty"`
+	// Markdown is a glamour style name, like "dark", "light" or "notty", or
+	// the path of a glamour JSON style.
+	Markdown string `json:"markdown,omitempty"`
+}
+
+// Keys selects a preset of key bindings, "default", "vim" or "emacs", and
+// binds single actions to other keys. An empty list unbinds an action.
+type Keys struct {
+	Preset   string              `json:"preset,omitempty"`
+	Bindings map[string][]string `json:"bindings,omitempty"`
+}
+
+func DefaultPath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, appName, configFile), nil
+}
+
+// Load reads the config file at path. A missing file is not an error and
+// results in the default config.
+func Load(path string) (*Config, error) {
+	cfg := &Config{}
+
+	data, err := os.ReadFile(path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("failed to read config: %w", err)
+	}
+
+	if err == nil {
+		if err := json.Unmarshal(data, cfg); err != nil {
+			return nil, fmt.Errorf("failed to parse config %s: %w", path, err)
+		}
+	}
+
+	if cfg.Terminal == "" {
+		cfg.Terminal = DefaultTerminal()
+	}
+	if cfg.DownloadDir == "" {
+		cfg.DownloadDir = "."
+	}
+
+	return cfg, nil
+}
+
+// ProfileName derives a profile name from a base URL. It is used when no
+// profile is given on the command line.
+func ProfileName(baseUrl string) string {
+	name := strings.TrimSpace(baseUrl)
+	if _, rest, ok := strings.Cut(name, "://"); ok {
+		name = rest
+	}
+	name = strings.TrimRight(name, "/")
+
+	return safeName(name)
+}
+
+// safeName replaces everything but letters, digits, dots and dashes with
+// underscores, so the name can't contain path separators.
+func safeName(name string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '.', r == '-':
+			return r
+		default:
+			return '_'
+		}
+	}, name)
+}
+
+// profileDir returns the directory of a profile below dir, refusing names
+// that would point elsewhere.
+func profileDir(dir, profile string) (string, error) {
+	name := safeName(profile)
+	if strings.Trim(name, ".") == "" {
+		return "", fmt.Errorf("invalid profile name %q", profile)
+	}
+	return filepath.Join(dir, name), nil
+}
+
+// CacheDir returns the directory used for cached responses of a profile.
+func CacheDir(profile string) (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return profileDir(filepath.Join(dir, appName), profile)
+}
+
+// NotesDir returns the directory of the notes on the challenges of a
+// profile. Unlike the cache, it is kept next to the config.
+func NotesDir(profile string) (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return profileDir(filepath.Join(dir, appName, "notes"), profile)
+}
+
+// Save writes the config to path. Defaults filled in by Load are left out.
+func (c *Config) Save(path string) error {
+	out := *c
+	if out.Terminal == DefaultTerminal() {
+		out.Terminal = ""
+	}
+	if out.DownloadDir == "." {
+		out.DownloadDir = ""
+	}
+
+	data, err := json.MarshalIndent(out, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return fmt.Errorf("failed to create config directory: %w", err)
+	}
+
+	if err := os.WriteFile(path, append(data, '\n'), 0o600); err != nil {
+		return fmt.Errorf("failed to write config: %w", err)
+	}
+	return nil
+}
+
+// DefaultTerminal returns the terminal template for the platform. The
+// command is escaped for every layer it passes through, see the template
+// functions in the README.
+func DefaultTerminal() string {
+	switch runtime.GOOS {
+	case "darwin":
+		return `osascript -e {{quote (printf "tell application \"Terminal\" to do script %s" (applescript .Command))}}`
+	case "windows":
+		return `start cmd /k {{cmdquote .Command}}`
+	default:
+		return `x-terminal-emulator -e sh -c {{quote .Command}}`
+	}
+}
@@ -0,0 +1,146 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jonsth131/ctfd-cli/api"
+)
+
+func TestLoadMissingFile(t *testing.T) {
+	cfg, err := Load(filepath.Join(t.TempDir(), "config.json"))
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if cfg.Terminal != DefaultTerminal() {
+		t.Errorf("expected default terminal, got %q", cfg.Terminal)
+	}
+	if cfg.DownloadDir != "." {
+		t.Errorf("expected default download dir, got %q", cfg.DownloadDir)
+	}
+}
+
+func TestLoadInvalidFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(path, []byte("{"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := Load(path); err == nil {
+		t.Errorf("expected error, got none")
+	}
+}
+
+func TestSaveAndLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ctfd-cli", "config.json")
+
+	cfg, _ := Load(path)
+	cfg.Profiles = map[string]Profile{
+		"example": {URL: "https://ctf.example.com", Session: "session=abc"},
+	}
+	if err := cfg.Save(path); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), `"terminal"`) {
+		t.Errorf("expected default terminal to be left out of the saved config")
+	}
+
+	loaded, err := Load(path)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if loaded.Profiles["example"].Session != "session=abc" {
+		t.Errorf("unexpected profile %+v", loaded.Profiles["example"])
+	}
+}
+
+func TestLoadKeys(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	data := `{"keys": {"preset": "vim", "bindings": {"quit": ["ctrl+q"], "hints": []}}}`
+	if err := os.WriteFile(path, []byte(data), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if cfg.Keys.Preset != "vim" {
+		t.Errorf("expected preset vim, got %q", cfg.Keys.Preset)
+	}
+	if keys := cfg.Keys.Bindings["quit"]; len(keys) != 1 || keys[0] != "ctrl+q" {
+		t.Errorf("unexpected quit keys %v", keys)
+	}
+	if keys, ok := cfg.Keys.Bindings["hints"]; !ok || len(keys) != 0 {
+		t.Errorf("expected hints to be unbound, got %v", keys)
+	}
+}
+
+func TestProfileName(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"https://ctf.example.com", "ctf.example.com"},
+		{"ctf.example.com:8000", "ctf.example.com_8000"},
+		{"https://example.com/ctf/", "example.com_ctf"},
+	}
+
+	for _, test := range tests {
+		if name := ProfileName(test.input); name != test.expected {
+			t.Errorf("ProfileName(%q) = %q, want %q", test.input, name, test.expected)
+		}
+	}
+}
+
+func TestProfileDir(t *testing.T) {
+	base := filepath.Join("base", "ctfd-cli")
+	tests := []struct {
+		profile  string
+		expected string
+		ok       bool
+	}{
+		{"example", filepath.Join(base, "example"), true},
+		{"ctf.example.com", filepath.Join(base, "ctf.example.com"), true},
+		{"../../x", filepath.Join(base, ".._.._x"), true},
+		{"a/b", filepath.Join(base, "a_b"), true},
+		{"..", "", false},
+		{".", "", false},
+		{"", "", false},
+	}
+
+	for _, test := range tests {
+		dir, err := profileDir(base, test.profile)
+		if (err == nil) != test.ok || dir != test.expected {
+			t.Errorf("profileDir(%q) = %q, %v, want %q", test.profile, dir, err, test.expected)
+		}
+	}
+}
+
+func TestProfileValidate(t *testing.T) {
+	gateway := api.NetworkOptions{BasicAuthUser: "team", BasicAuthPassword: "secret"}
+
+	tests := []struct {
+		name    string
+		profile Profile
+		valid   bool
+	}{
+		{"Token", Profile{Token: "ctfd_abc"}, true},
+		{"Basic auth", Profile{Password: "hunter2", Network: gateway}, true},
+		{"Token and basic auth", Profile{Token: "ctfd_abc", Network: gateway}, false},
+		{"Auto token and basic auth", Profile{AutoToken: true, Network: gateway}, false},
+	}
+
+	for _, test := range tests {
+		if err := test.profile.Validate(); (err == nil) != test.valid {
+			t.Errorf("%s: unexpected result %v", test.name, err)
+		}
+	}
+}
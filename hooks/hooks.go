@@ -0,0 +1,236 @@
+package hooks
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"os/exec"
+	"runtime"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/jonsth131/ctfd-cli/api"
+	"github.com/jonsth131/ctfd-cli/config"
+)
+
+// Events hooks can be run on.
+const (
+	Solve        = "solve"
+	NewChallenge = "new_challenge"
+	Notification = "notification"
+	RankChange   = "rank_change"
+)
+
+var Events = []string{Solve, NewChallenge, Notification, RankChange}
+
+// Event is something that happened in the CTF. Challenge is set for solves
+// and new challenges, Message for notifications and PreviousRank for rank
+// changes.
+type Event struct {
+	Name         string
+	Challenge    api.ListChallenge
+	User         string
+	Team         string
+	Score        int32
+	Rank         string
+	PreviousRank string
+	Message      string
+}
+
+// Env returns the details of the event as environment variables.
+func (e Event) Env() []string {
+	return []string{
+		"CTFD_EVENT=" + e.Name,
+		"CTFD_CHALLENGE_ID=" + idString(e.Challenge.Id),
+		"CTFD_CHALLENGE_NAME=" + e.Challenge.Name,
+		"CTFD_CHALLENGE_CATEGORY=" + e.Challenge.Category,
+		"CTFD_CHALLENGE_VALUE=" + idString(e.Challenge.Value),
+		"CTFD_USER=" + e.User,
+		"CTFD_TEAM=" + e.Team,
+		"CTFD_SCORE=" + strconv.Itoa(int(e.Score)),
+		"CTFD_RANK=" + e.Rank,
+		"CTFD_PREVIOUS_RANK=" + e.PreviousRank,
+		"CTFD_MESSAGE=" + e.Message,
+	}
+}
+
+func idString(n uint32) string {
+	if n == 0 {
+		return ""
+	}
+	return strconv.FormatUint(uint64(n), 10)
+}
+
+// Runner runs the hooks from the config.
+type Runner struct {
+	hooks []config.Hook
+	// start runs a command, it is replaced in tests.
+	start func(cmd *exec.Cmd) error
+}
+
+// NewRunner checks the hooks. An unknown event or an empty command is an
+// error.
+func NewRunner(hooks []config.Hook) (*Runner, error) {
+	for i, h := range hooks {
+		if !slices.Contains(Events, h.Event) {
+			return nil, fmt.Errorf("unknown event %q in hook %d, expected one of %s", h.Event, i+1, strings.Join(Events, ", "))
+		}
+		if strings.TrimSpace(h.Command) == "" {
+			return nil, fmt.Errorf("missing command in hook %d", i+1)
+		}
+	}
+	return &Runner{hooks: hooks, start: startCommand}, nil
+}
+
+// Wants reports whether any hook runs on event.
+func (r *Runner) Wants(event string) bool {
+	for _, h := range r.hooks {
+		if h.Event == event {
+			return true
+		}
+	}
+	return false
+}
+
+// Run starts the hooks of the event without waiting for them. The details of
+// the event are only passed in the environment, never pasted into the
+// command, since names and messages are chosen by others.
+func (r *Runner) Run(e Event) {
+	for _, h := range r.hooks {
+		if h.Event != e.Name {
+			continue
+		}
+
+		var cmd *exec.Cmd
+		if runtime.GOOS == "windows" {
+			cmd = exec.Command("cmd", "/c", h.Command)
+		} else {
+			cmd = exec.Command("sh", "-c", h.Command)
+		}
+		cmd.Env = append(os.Environ(), e.Env()...)
+		log.Default().Printf("Running hook for %s: %s", e.Name, h.Command)
+		if err := r.start(cmd); err != nil {
+			log.Default().Printf("Hook for %s: %v", e.Name, err)
+		}
+	}
+}
+
+func startCommand(cmd *exec.Cmd) error {
+	// The TUI owns the terminal, the output of hooks would garble it.
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = nil, nil, nil
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	go func() {
+		if err := cmd.Wait(); err != nil {
+			log.Default().Printf("Hook %q: %v", cmd.String(), err)
+		}
+	}()
+	return nil
+}
+
+// Snapshot is what is known about the CTF at one point. Nil slices and empty
+// strings are unknown, e.g. because fetching them failed.
+type Snapshot struct {
+	Challenges    []api.ListChallenge
+	Notifications []api.Notification
+	User          string
+	Team          string
+	Score         int32
+	Rank          string
+}
+
+// Watcher turns snapshots into events by comparing each with the one before.
+type Watcher struct {
+	mu            sync.Mutex
+	challenges    map[uint32]api.ListChallenge
+	notifications map[uint32]bool
+	user          string
+	team          string
+	score         int32
+	rank          string
+}
+
+// Observe returns the events since the previous snapshot. The first known
+// value of each part only sets what is already there, so starting the TUI
+// doesn't fire hooks for every existing challenge.
+func (w *Watcher) Observe(s Snapshot) []Event {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	var events []Event
+	event := func(name string) Event {
+		return Event{Name: name, User: w.user, Team: w.team, Score: w.score, Rank: w.rank}
+	}
+
+	if s.User != "" {
+		w.user, w.team, w.score = s.User, s.Team, s.Score
+	}
+
+	if s.Challenges != nil {
+		if w.challenges != nil {
+			for _, c := range s.Challenges {
+				old, ok := w.challenges[c.Id]
+				if !ok {
+					e := event(NewChallenge)
+					e.Challenge = c
+					events = append(events, e)
+				}
+				if c.SolvedByMe && !old.SolvedByMe {
+					e := event(Solve)
+					e.Challenge = c
+					events = append(events, e)
+				}
+			}
+		}
+		w.challenges = make(map[uint32]api.ListChallenge, len(s.Challenges))
+		for _, c := range s.Challenges {
+			w.challenges[c.Id] = c
+		}
+	}
+
+	if s.Notifications != nil {
+		if w.notifications != nil {
+			for _, n := range s.Notifications {
+				if !w.notifications[n.Id] {
+					e := event(Notification)
+					e.Message = notificationMessage(n)
+					events = append(events, e)
+				}
+			}
+		}
+		w.notifications = make(map[uint32]bool, len(s.Notifications))
+		for _, n := range s.Notifications {
+			w.notifications[n.Id] = true
+		}
+	}
+
+	if s.Rank != "" {
+		if w.rank != "" && s.Rank != w.rank {
+			e := event(RankChange)
+			e.Rank, e.PreviousRank = s.Rank, w.rank
+			events = append(events, e)
+		}
+		w.rank = s.Rank
+	}
+	// Events before the rank change carry the rank at the time of the
+	// snapshot too.
+	for i := range events {
+		if events[i].Name != RankChange {
+			events[i].Rank = w.rank
+		}
+	}
+	return events
+}
+
+func notificationMessage(n api.Notification) string {
+	if n.Title == "" {
+		return n.Content
+	}
+	if n.Content == "" {
+		return n.Title
+	}
+	return n.Title + ": " + n.Content
+}
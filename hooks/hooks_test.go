@@ -0,0 +1,133 @@
+package hooks
+
+import (
+	"os/exec"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/jonsth131/ctfd-cli/api"
+	"github.com/jonsth131/ctfd-cli/config"
+)
+
+func names(events []Event) []string {
+	var n []string
+	for _, e := range events {
+		n = append(n, e.Name)
+	}
+	return n
+}
+
+func TestObserve(t *testing.T) {
+	var w Watcher
+	base := Snapshot{
+		Challenges:    []api.ListChallenge{{Id: 1, Name: "Warmup"}},
+		Notifications: []api.Notification{{Id: 1, Title: "Welcome"}},
+		User:          "alice",
+		Team:          "team",
+		Score:         0,
+		Rank:          "5th",
+	}
+	if events := w.Observe(base); len(events) != 0 {
+		t.Fatalf("expected no events for the first snapshot, got %v", names(events))
+	}
+
+	next := Snapshot{
+		Challenges: []api.ListChallenge{
+			{Id: 1, Name: "Warmup", Category: "misc", SolvedByMe: true},
+			{Id: 2, Name: "Pwn me"},
+		},
+		Notifications: []api.Notification{{Id: 1, Title: "Welcome"}, {Id: 2, Title: "Hint", Content: "Look closer"}},
+		User:          "alice",
+		Team:          "team",
+		Score:         100,
+		Rank:          "3rd",
+	}
+	events := w.Observe(next)
+	want := []string{Solve, NewChallenge, Notification, RankChange}
+	if !slices.Equal(names(events), want) {
+		t.Fatalf("expected %v, got %v", want, names(events))
+	}
+
+	solve := events[0]
+	if solve.Challenge.Name != "Warmup" || solve.Score != 100 || solve.Rank != "3rd" || solve.Team != "team" {
+		t.Errorf("unexpected solve event %+v", solve)
+	}
+	if events[2].Message != "Hint: Look closer" {
+		t.Errorf("unexpected notification message %q", events[2].Message)
+	}
+	if events[3].Rank != "3rd" || events[3].PreviousRank != "5th" {
+		t.Errorf("unexpected rank change %+v", events[3])
+	}
+
+	if events := w.Observe(next); len(events) != 0 {
+		t.Errorf("expected no events without changes, got %v", names(events))
+	}
+}
+
+func TestObserveUnknown(t *testing.T) {
+	var w Watcher
+	w.Observe(Snapshot{Challenges: []api.ListChallenge{{Id: 1}}, Rank: "1st"})
+
+	// A failed fetch is not the same as everything disappearing.
+	if events := w.Observe(Snapshot{}); len(events) != 0 {
+		t.Fatalf("expected no events, got %v", names(events))
+	}
+	events := w.Observe(Snapshot{Challenges: []api.ListChallenge{{Id: 1}, {Id: 2}}, Rank: "1st"})
+	if !slices.Equal(names(events), []string{NewChallenge}) {
+		t.Errorf("expected a new challenge, got %v", names(events))
+	}
+
+	// Notifications seen for the first time are the baseline.
+	if events := w.Observe(Snapshot{Notifications: []api.Notification{{Id: 1}}}); len(events) != 0 {
+		t.Errorf("expected no events, got %v", names(events))
+	}
+}
+
+func TestNewRunner(t *testing.T) {
+	if _, err := NewRunner([]config.Hook{{Event: "flag", Command: "true"}}); err == nil || !strings.Contains(err.Error(), "unknown event") {
+		t.Errorf("expected unknown event error, got %v", err)
+	}
+	if _, err := NewRunner([]config.Hook{{Event: Solve, Command: " "}}); err == nil || !strings.Contains(err.Error(), "missing command") {
+		t.Errorf("expected missing command error, got %v", err)
+	}
+
+	r, err := NewRunner([]config.Hook{{Event: Solve, Command: "true"}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !r.Wants(Solve) || r.Wants(Notification) {
+		t.Error("expected the runner to only want solves")
+	}
+}
+
+func TestRun(t *testing.T) {
+	r, err := NewRunner([]config.Hook{
+		{Event: Solve, Command: `notify-send "$CTFD_CHALLENGE_NAME" {{.Challenge.Name}}`},
+		{Event: RankChange, Command: "true"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var started []*exec.Cmd
+	r.start = func(cmd *exec.Cmd) error {
+		started = append(started, cmd)
+		return nil
+	}
+
+	r.Run(Event{Name: Solve, Challenge: api.ListChallenge{Id: 4, Name: "It's easy"}, Score: 50})
+
+	if len(started) != 1 {
+		t.Fatalf("expected one command, got %d", len(started))
+	}
+	cmd := started[0]
+	// The command is run as written, names only reach it in the environment.
+	if line := cmd.Args[len(cmd.Args)-1]; line != `notify-send "$CTFD_CHALLENGE_NAME" {{.Challenge.Name}}` {
+		t.Errorf("unexpected command %q", line)
+	}
+	for _, env := range []string{"CTFD_EVENT=solve", "CTFD_CHALLENGE_ID=4", "CTFD_CHALLENGE_NAME=It's easy", "CTFD_SCORE=50"} {
+		if !slices.Contains(cmd.Env, env) {
+			t.Errorf("expected %s in the environment", env)
+		}
+	}
+}
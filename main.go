@@ -3,19 +3,135 @@ package main
 import (
 	"flag"
 	"fmt"
+	"os"
 
+	"github.com/jonsth131/ctfd-cli/api"
+	"github.com/jonsth131/ctfd-cli/config"
 	"github.com/jonsth131/ctfd-cli/tui"
 )
 
 func main() {
 	baseUrl := flag.String("baseurl", "", "Base URL for API requests")
 	logging := flag.Bool("log", false, "Log to file")
+	configPath := flag.String("config", "", "Path to config file")
+	user := flag.String("user", "", "Username for commands (password is read from CTFD_PASSWORD)")
+	profile := flag.String("profile", "", "Profile from the config file to use")
+	offline := flag.Bool("offline", false, "Only show cached data, never contact the server")
+	apiOpts := api.DefaultOptions()
+	flag.DurationVar(&apiOpts.Timeout, "timeout", apiOpts.Timeout, "Timeout for a single request attempt to respond")
+	flag.IntVar(&apiOpts.Retries, "retries", apiOpts.Retries, "Number of retries for failed idempotent requests")
+	proxy := flag.String("proxy", "", "HTTP or SOCKS5 proxy URL, overrides the profile's proxy")
+	flag.Usage = usage
 	flag.Parse()
 
+	// The claim board is shared by the team and belongs to no profile.
+	if flag.Arg(0) == "team-server" {
+		if err := teamServerCommand(flag.Args()[1:]); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if *configPath == "" {
+		path, err := config.DefaultPath()
+		if err != nil {
+			fmt.Println("Failed to find config directory:", err)
+			os.Exit(1)
+		}
+		*configPath = path
+	}
+
+	cfg, err := config.Load(*configPath)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	if *profile != "" {
+		p, ok := cfg.Profiles[*profile]
+		if !ok && *baseUrl == "" {
+			fmt.Printf("Unknown profile %q\n", *profile)
+			os.Exit(1)
+		}
+		if *baseUrl == "" {
+			*baseUrl = p.URL
+		}
+	}
+
 	if *baseUrl == "" {
-		fmt.Println("Please provide a base URL with -baseurl")
+		fmt.Println("Please provide a base URL with -baseurl or a profile with -profile")
 		return
 	}
 
-	tui.StartTea(*baseUrl, *logging)
+	if *profile == "" {
+		*profile = config.ProfileName(*baseUrl)
+	}
+
+	if err := cfg.Profiles[*profile].Validate(); err != nil {
+		fmt.Printf("Invalid profile %q: %v\n", *profile, err)
+		os.Exit(1)
+	}
+
+	apiOpts.Network = cfg.Profiles[*profile].Network
+	if *proxy != "" {
+		apiOpts.Network.Proxy = *proxy
+	}
+	if apiOpts.Network.Insecure {
+		fmt.Fprintln(os.Stderr, "WARNING: TLS certificate verification is disabled for this profile. Anyone on the network can read and modify your traffic, including your session and flags.")
+	}
+
+	if flag.NArg() > 0 {
+		env := commandEnv{
+			baseUrl:    *baseUrl,
+			user:       *user,
+			opts:       apiOpts,
+			cfg:        cfg,
+			configPath: *configPath,
+			profile:    *profile,
+		}
+		if err := runCommand(env, flag.Args()); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	cacheDir, err := config.CacheDir(*profile)
+	if err != nil {
+		fmt.Println("Failed to find cache directory:", err)
+		os.Exit(1)
+	}
+
+	notesDir, err := config.NotesDir(*profile)
+	if err != nil {
+		fmt.Println("Failed to find notes directory:", err)
+		os.Exit(1)
+	}
+
+	tui.StartTea(tui.Options{
+		BaseURL:     *baseUrl,
+		Logging:     *logging,
+		Offline:     *offline,
+		CacheDir:    cacheDir,
+		NotesDir:    notesDir,
+		Config:      cfg,
+		ConfigPath:  *configPath,
+		ProfileName: *profile,
+		Profile:     cfg.Profiles[*profile],
+		ApiOptions:  apiOpts,
+	})
+}
+
+func usage() {
+	fmt.Fprintf(flag.CommandLine.Output(), "Usage of %s:\n", os.Args[0])
+	fmt.Fprintf(flag.CommandLine.Output(), "  %s [flags]                    start the TUI\n", os.Args[0])
+	fmt.Fprintf(flag.CommandLine.Output(), "  %s [flags] challenges         print all challenges as JSON\n", os.Args[0])
+	fmt.Fprintf(flag.CommandLine.Output(), "  %s [flags] challenge <id>     print a challenge as JSON\n", os.Args[0])
+	fmt.Fprintf(flag.CommandLine.Output(), "  %s [flags] import-session     import cookies from a browser into the profile\n", os.Args[0])
+	fmt.Fprintf(flag.CommandLine.Output(), "  %s [flags] oauth-login        login with the CTF's OAuth provider and save the session\n", os.Args[0])
+	fmt.Fprintf(flag.CommandLine.Output(), "  %s [flags] token ...          create, list or revoke API tokens\n", os.Args[0])
+	fmt.Fprintf(flag.CommandLine.Output(), "  %s [flags] notes [<id>]       print the notes on a challenge or export all notes\n", os.Args[0])
+	fmt.Fprintf(flag.CommandLine.Output(), "  %s [flags] team-server        serve a claim board for the team\n\n", os.Args[0])
+	flag.PrintDefaults()
 }
@@ -0,0 +1,98 @@
+package notes
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Store keeps private markdown notes on challenges, one file per challenge
+// named after its id.
+type Store struct {
+	dir string
+}
+
+func New(dir string) *Store {
+	return &Store{dir: dir}
+}
+
+// Path returns the file holding the notes on a challenge, creating the
+// directory of the store so an editor can write it.
+func (s *Store) Path(id int) (string, error) {
+	if err := os.MkdirAll(s.dir, 0o700); err != nil {
+		return "", fmt.Errorf("failed to create notes directory: %w", err)
+	}
+	return s.path(id), nil
+}
+
+func (s *Store) path(id int) string {
+	return filepath.Join(s.dir, fmt.Sprintf("%d.md", id))
+}
+
+// Get returns the notes on a challenge, or "" if there are none.
+func (s *Store) Get(id int) (string, error) {
+	data, err := os.ReadFile(s.path(id))
+	if errors.Is(err, os.ErrNotExist) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to read notes: %w", err)
+	}
+	return string(data), nil
+}
+
+// Has reports whether there are notes on a challenge. Files left empty by
+// an editor don't count.
+func (s *Store) Has(id int) bool {
+	text, err := s.Get(id)
+	return err == nil && strings.TrimSpace(text) != ""
+}
+
+// Set replaces the notes on a challenge. Empty notes remove the file.
+func (s *Store) Set(id int, text string) error {
+	if strings.TrimSpace(text) == "" {
+		if err := os.Remove(s.path(id)); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("failed to remove notes: %w", err)
+		}
+		return nil
+	}
+
+	p, err := s.Path(id)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(p, []byte(text), 0o600); err != nil {
+		return fmt.Errorf("failed to write notes: %w", err)
+	}
+	return nil
+}
+
+// List returns the ids of the challenges with notes in ascending order.
+func (s *Store) List() ([]int, error) {
+	entries, err := os.ReadDir(s.dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to list notes: %w", err)
+	}
+
+	var ids []int
+	for _, e := range entries {
+		name, ok := strings.CutSuffix(e.Name(), ".md")
+		if !ok || e.IsDir() {
+			continue
+		}
+		id, err := strconv.Atoi(name)
+		if err != nil || !s.Has(id) {
+			continue
+		}
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+	return ids, nil
+}
@@ -0,0 +1,90 @@
+package notes
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestGetMissing(t *testing.T) {
+	s := New(filepath.Join(t.TempDir(), "notes"))
+
+	text, err := s.Get(1)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if text != "" || s.Has(1) {
+		t.Errorf("expected no notes, got %q", text)
+	}
+
+	ids, err := s.List()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(ids) != 0 {
+		t.Errorf("expected no ids, got %v", ids)
+	}
+}
+
+func TestSetAndList(t *testing.T) {
+	s := New(filepath.Join(t.TempDir(), "notes"))
+
+	if err := s.Set(12, "## Exploit\n\nuse the format string\n"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := s.Set(3, "xor key is 0x41"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	text, err := s.Get(12)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if text != "## Exploit\n\nuse the format string\n" {
+		t.Errorf("unexpected notes %q", text)
+	}
+
+	ids, err := s.List()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !slices.Equal(ids, []int{3, 12}) {
+		t.Errorf("expected ids [3 12], got %v", ids)
+	}
+
+	if err := s.Set(3, "  \n"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if s.Has(3) {
+		t.Error("expected empty notes to be removed")
+	}
+}
+
+func TestListSkipsEmptyAndOtherFiles(t *testing.T) {
+	dir := t.TempDir()
+	s := New(dir)
+
+	p, err := s.Path(5)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// An editor that was quit without saving leaves an empty file.
+	if err := os.WriteFile(p, nil, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "readme.md"), []byte("not a challenge"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "7.md.swp"), []byte("swap"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	ids, err := s.List()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(ids) != 0 {
+		t.Errorf("expected no ids, got %v", ids)
+	}
+}
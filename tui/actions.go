@@ -0,0 +1,157 @@
+package tui
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+	"text/template"
+
+	"github.com/atotto/clipboard"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/jonsth131/ctfd-cli/api"
+	"github.com/jonsth131/ctfd-cli/tui/constants"
+)
+
+func copyCmd(text string) tea.Cmd {
+	return func() tea.Msg {
+		if err := clipboard.WriteAll(text); err != nil {
+			return createErrMsg(fmt.Errorf("Failed to copy to clipboard: %v", err))
+		}
+		return messageSetMsg{fmt.Sprintf("Copied %q to clipboard", text)}
+	}
+}
+
+func openURLCmd(u string) tea.Cmd {
+	return func() tea.Msg {
+		var cmd *exec.Cmd
+		switch runtime.GOOS {
+		case "darwin":
+			cmd = exec.Command("open", u)
+		case "windows":
+			cmd = exec.Command("rundll32", "url.dll,FileProtocolHandler", u)
+		default:
+			cmd = exec.Command("xdg-open", u)
+		}
+		if err := cmd.Start(); err != nil {
+			return createErrMsg(fmt.Errorf("Failed to open %s: %v", u, err))
+		}
+		go cmd.Wait()
+		return messageSetMsg{fmt.Sprintf("Opened %s", u)}
+	}
+}
+
+func terminalCmd(command string) tea.Cmd {
+	return func() tea.Msg {
+		line, err := renderTerminalTemplate(constants.Config.Terminal, command)
+		if err != nil {
+			return createErrMsg(fmt.Errorf("Invalid terminal template: %v", err))
+		}
+
+		log.Default().Printf("Spawning terminal: %s", line)
+		var cmd *exec.Cmd
+		if runtime.GOOS == "windows" {
+			cmd = exec.Command("cmd", "/c", line)
+		} else {
+			cmd = exec.Command("sh", "-c", line)
+		}
+		if err := cmd.Start(); err != nil {
+			return createErrMsg(fmt.Errorf("Failed to spawn terminal: %v", err))
+		}
+		go cmd.Wait()
+		return messageSetMsg{fmt.Sprintf("Started %s", command)}
+	}
+}
+
+func renderTerminalTemplate(tmpl, command string) (string, error) {
+	t, err := template.New("terminal").Funcs(template.FuncMap{
+		"quote":       shellQuote,
+		"applescript": appleScriptQuote,
+		"cmdquote":    cmdQuote,
+	}).Parse(tmpl)
+	if err != nil {
+		return "", err
+	}
+
+	var b strings.Builder
+	if err := t.Execute(&b, struct{ Command string }{command}); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
+
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// appleScriptQuote returns s as an AppleScript string literal.
+func appleScriptQuote(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`
+}
+
+// cmdQuote escapes the characters cmd.exe treats specially with carets, so
+// the line reaches the program cmd starts unchanged.
+func cmdQuote(s string) string {
+	return cmdSpecial.Replace(s)
+}
+
+var cmdSpecial = strings.NewReplacer(
+	"^", "^^", "&", "^&", "|", "^|", "<", "^<", ">", "^>",
+	"(", "^(", ")", "^)", "%", "^%", "!", "^!", `"`, `^"`,
+)
+
+// editorCmd opens path in editor. An editor with arguments, such as
+// "code --wait", is run by the shell with the path quoted.
+func editorCmd(editor, path string) *exec.Cmd {
+	if len(strings.Fields(editor)) == 1 {
+		return exec.Command(strings.TrimSpace(editor), path)
+	}
+	if runtime.GOOS == "windows" {
+		return exec.Command("cmd", "/c", editor+` "`+path+`"`)
+	}
+	return exec.Command("sh", "-c", editor+" "+shellQuote(path))
+}
+
+// connectionCmd runs the default action for a connection: URLs are opened in
+// the browser, everything else is started in a new terminal.
+func connectionCmd(c api.Connection) tea.Cmd {
+	if c.Kind == api.ConnectionURL {
+		return openURLCmd(c.URL)
+	}
+	return terminalCmd(c.Command())
+}
+
+type notesEditedMsg struct {
+	id int
+}
+
+// editNotesCmd suspends the TUI and opens the notes on a challenge in the
+// editor from $VISUAL or $EDITOR.
+func editNotesCmd(id int) tea.Cmd {
+	path, err := constants.Notes.Path(id)
+	if err != nil {
+		return func() tea.Msg { return createErrMsg(err) }
+	}
+
+	editor := os.Getenv("VISUAL")
+	if editor == "" {
+		editor = os.Getenv("EDITOR")
+	}
+	if editor == "" {
+		editor = "vi"
+		if runtime.GOOS == "windows" {
+			editor = "notepad"
+		}
+	}
+	cmd := editorCmd(editor, path)
+
+	log.Default().Printf("Editing notes: %s", cmd)
+	return tea.ExecProcess(cmd, func(err error) tea.Msg {
+		if err != nil {
+			return createErrMsg(fmt.Errorf("Failed to edit notes: %v", err))
+		}
+		return notesEditedMsg{id}
+	})
+}
@@ -0,0 +1,63 @@
+package tui
+
+import (
+	"runtime"
+	"slices"
+	"testing"
+)
+
+func TestEditorCmd(t *testing.T) {
+	path := "/tmp/my notes/1.md"
+
+	cmd := editorCmd("vim", path)
+	if !slices.Equal(cmd.Args, []string{"vim", path}) {
+		t.Errorf("expected the editor to be run directly, got %q", cmd.Args)
+	}
+
+	cmd = editorCmd("code --wait", path)
+	want := []string{"sh", "-c", "code --wait '/tmp/my notes/1.md'"}
+	if runtime.GOOS == "windows" {
+		want = []string{"cmd", "/c", `code --wait "/tmp/my notes/1.md"`}
+	}
+	if !slices.Equal(cmd.Args, want) {
+		t.Errorf("expected %q, got %q", want, cmd.Args)
+	}
+}
+
+func TestRenderTerminalTemplate(t *testing.T) {
+	command := `echo "a\b" 'c' & calc`
+
+	tests := []struct {
+		name     string
+		tmpl     string
+		expected string
+	}{
+		{
+			"Shell",
+			`x-terminal-emulator -e sh -c {{quote .Command}}`,
+			`x-terminal-emulator -e sh -c 'echo "a\b" '\''c'\'' & calc'`,
+		},
+		{
+			"macOS",
+			`osascript -e {{quote (printf "tell application \"Terminal\" to do script %s" (applescript .Command))}}`,
+			`osascript -e 'tell application "Terminal" to do script "echo \"a\\b\" '\''c'\'' & calc"'`,
+		},
+		{
+			"Windows",
+			`start cmd /k {{cmdquote .Command}}`,
+			`start cmd /k echo ^"a\b^" 'c' ^& calc`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			line, err := renderTerminalTemplate(test.tmpl, command)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if line != test.expected {
+				t.Errorf("expected %s, got %s", test.expected, line)
+			}
+		})
+	}
+}
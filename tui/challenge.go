@@ -4,7 +4,8 @@ import (
 	"context"
 	"fmt"
 	"log"
-	"path"
+	"os"
+	"path/filepath"
 	"strconv"
 	"strings"
 
@@ -13,41 +14,61 @@ import (
 	"github.com/charmbracelet/bubbles/textinput"
 	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
-	"github.com/charmbracelet/glamour"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/jonsth131/ctfd-cli/api"
+	"github.com/jonsth131/ctfd-cli/cache"
 	"github.com/jonsth131/ctfd-cli/tui/constants"
 )
 
 type challengeKeymap struct {
-	Back   key.Binding
-	Reload key.Binding
-	Submit key.Binding
-	Quit   key.Binding
+	Back           key.Binding
+	Reload         key.Binding
+	Submit         key.Binding
+	Hints          key.Binding
+	Download       key.Binding
+	NextConnection key.Binding
+	Copy           key.Binding
+	Open           key.Binding
+	Notes          key.Binding
+	Claim          key.Binding
+	Help           key.Binding
+	Quit           key.Binding
 }
 
 func (k challengeKeymap) ShortHelp() []key.Binding {
-	return []key.Binding{k.Back, k.Reload, k.Submit, k.Quit}
+	return []key.Binding{k.Back, k.Reload, k.Submit, k.Hints, k.Download, k.NextConnection, k.Copy, k.Open, k.Notes, k.Claim, k.Help, k.Quit}
 }
 
 func (k challengeKeymap) FullHelp() [][]key.Binding {
-	return [][]key.Binding{
-		k.ShortHelp(),
-	}
+	return fullHelp("Challenge")
 }
 
-var ChallengeKeymap = challengeKeymap{
-	Back:   constants.Keymap.Back,
-	Reload: constants.Keymap.Reload,
-	Submit: key.NewBinding(
-		key.WithKeys("s"),
-		key.WithHelp("s", "submit flag"),
-	),
-	Quit: constants.Keymap.Quit,
+func newChallengeKeymap() challengeKeymap {
+	return challengeKeymap{
+		Back:           constants.Keymap.Back,
+		Reload:         constants.Keymap.Reload,
+		Submit:         constants.Binding(constants.ActionSubmit),
+		Hints:          constants.Binding(constants.ActionHints),
+		Download:       constants.Binding(constants.ActionDownload),
+		NextConnection: constants.Binding(constants.ActionNextConnection),
+		Copy:           constants.Binding(constants.ActionCopy),
+		Open:           constants.Binding(constants.ActionOpen),
+		Notes:          constants.Binding(constants.ActionNotes),
+		Claim:          constants.Binding(constants.ActionClaim),
+		Help:           constants.Keymap.Help,
+		Quit:           constants.Keymap.Quit,
+	}
 }
 
+var ChallengeKeymap = newChallengeKeymap()
+
 type challengeUpdatedMsg struct {
 	challenge *api.Challenge
+	stale     cache.Status
+}
+
+type hintsFetchedMsg struct {
+	hints []api.Hint
 }
 
 type messageSetMsg struct {
@@ -62,43 +83,104 @@ const (
 )
 
 type challengeModel struct {
-	mode      mode
-	viewport  viewport.Model
-	challenge *api.Challenge
-	help      help.Model
-	input     textinput.Model
-	err       error
-	message   string
-	width     int
-	height    int
+	mode       mode
+	id         int
+	viewport   viewport.Model
+	challenge  *api.Challenge
+	hints      []api.Hint
+	notes      string
+	connection int
+	reqs       *requests
+	help       help.Model
+	input      textinput.Model
+	err        error
+	stale      cache.Status
+	message    string
+	width      int
+	height     int
 }
 
-func fetchChallengeCmd(id int) tea.Cmd {
-	return func() tea.Msg {
-		ctx, cancel := context.WithTimeout(context.Background(), constants.Timeout)
+func fetchChallengeCmd(r *requests, id int) tea.Cmd {
+	return r.sessionCmd(func(ctx context.Context) tea.Msg {
+		ctx, cancel := context.WithTimeout(ctx, constants.Timeout)
 		defer cancel()
+		ctx, status := cache.WithStatus(ctx)
 		log.Default().Printf("Fetching challenge %d...", id)
 		challenge, err := constants.C.GetChallenge(ctx, uint16(id))
 		if err != nil {
-			return createErrMsg(fmt.Errorf("Failed to fetch challenge %d: %v", id, err))
+			return createErrMsg(fmt.Errorf("Failed to fetch challenge %d: %w", id, err))
 		}
 
 		log.Default().Printf("Fetched challenge %d", id)
-		return challengeUpdatedMsg{challenge}
+		return challengeUpdatedMsg{challenge, *status}
+	})
+}
+
+func fetchHintsCmd(r *requests, challenge api.Challenge) tea.Cmd {
+	return r.sessionCmd(func(ctx context.Context) tea.Msg {
+		ctx, cancel := context.WithTimeout(ctx, constants.Timeout)
+		defer cancel()
+		hints := make([]api.Hint, 0, len(challenge.Hints))
+		for _, h := range challenge.Hints {
+			log.Default().Printf("Fetching hint %d...", h.Id)
+			hint, err := constants.C.GetHint(ctx, h.Id)
+			if err != nil {
+				return createErrMsg(fmt.Errorf("Failed to fetch hint %d: %w", h.Id, err))
+			}
+			hints = append(hints, *hint)
+		}
+		return hintsFetchedMsg{hints}
+	})
+}
+
+func downloadFilesCmd(r *requests, challenge api.Challenge) tea.Cmd {
+	return r.progressCmd(func(ctx context.Context, progress func(done, total int)) tea.Msg {
+		// No deadline, each attempt only times out waiting for the response
+		// so large files aren't cut off while they keep arriving.
+		dir, err := saveChallengeFiles(ctx, challenge, progress)
+		if err != nil {
+			return createErrMsg(err)
+		}
+		return messageSetMsg{fmt.Sprintf("Downloaded %d files to %s", len(challenge.Files), dir)}
+	})
+}
+
+// saveChallengeFiles downloads the files of a challenge to its directory in
+// the download dir and returns the directory. progress, if set, is called
+// after each file.
+func saveChallengeFiles(ctx context.Context, challenge api.Challenge, progress func(done, total int)) (string, error) {
+	dir := filepath.Join(constants.Config.DownloadDir, safeName(challenge.Name))
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", fmt.Errorf("Failed to create %s: %v", dir, err)
 	}
+	for i, f := range challenge.Files {
+		log.Default().Printf("Downloading %s...", f)
+		data, err := constants.C.DownloadFile(ctx, f)
+		if err != nil {
+			return "", fmt.Errorf("Failed to download %s: %w", api.FileName(f), err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, safeName(api.FileName(f))), data, 0o644); err != nil {
+			return "", fmt.Errorf("Failed to save %s: %v", api.FileName(f), err)
+		}
+		if progress != nil {
+			progress(i+1, len(challenge.Files))
+		}
+	}
+	return dir, nil
 }
 
-func submitFlagCmd(id int, flag string) tea.Cmd {
-	return func() tea.Msg {
-		ctx, cancel := context.WithTimeout(context.Background(), constants.Timeout)
+func submitFlagCmd(r *requests, id int, flag string) tea.Cmd {
+	return r.sessionCmd(func(ctx context.Context) tea.Msg {
+		ctx, cancel := context.WithTimeout(ctx, constants.Timeout)
 		defer cancel()
 		log.Default().Printf("Submitting flag: %s for challenge: %d", flag, id)
 		result, err := constants.C.SubmitFlag(ctx, id, flag)
 		if err != nil {
-			return createErrMsg(fmt.Errorf("Failed to submit flag for challenge %d: %v", id, err))
+			return createErrMsg(fmt.Errorf("Failed to submit flag for challenge %d: %w", id, err))
 		}
+		updateSolved(result)
 		return messageSetMsg{result.Message}
-	}
+	})
 }
 
 func InitChallenge(id int, width, height int) (challengeModel, tea.Cmd) {
@@ -109,7 +191,9 @@ func InitChallenge(id int, width, height int) (challengeModel, tea.Cmd) {
 	input.Width = 50
 
 	m := challengeModel{
-		challenge: nil,
+		id:        id,
+		challenge: shared.getChallenge(id),
+		reqs:      newRequests(),
 		help:      help.New(),
 		err:       nil,
 		message:   "",
@@ -119,11 +203,14 @@ func InitChallenge(id int, width, height int) (challengeModel, tea.Cmd) {
 		height:    height,
 	}
 
+	m.notes, m.err = constants.Notes.Get(id)
+
 	top, right, bottom, left := constants.DocStyle.GetMargin()
-	m.viewport = viewport.New(width-left-right, height-top-bottom-5)
+	m.viewport = viewport.New(width-left-right, height-top-bottom-6)
 	m.viewport.Style = lipgloss.NewStyle().Align(lipgloss.Bottom)
+	m.setViewportContent()
 
-	return m, fetchChallengeCmd(id)
+	return m, fetchChallengeCmd(m.reqs, id)
 }
 
 func formatChallenge(challenge api.Challenge) string {
@@ -132,15 +219,23 @@ func formatChallenge(challenge api.Challenge) string {
 		var formatted []string
 
 		for _, fullURL := range challenge.Files {
-			cleanPath := strings.Split(fullURL, "?")[0]
-			filename := path.Base(cleanPath)
-
-			formatted = append(formatted, fmt.Sprintf("- %s", filename))
+			formatted = append(formatted, fmt.Sprintf("- %s", api.FileName(fullURL)))
 		}
 
 		files = fmt.Sprintf("\n\n## Files:\n\n%s", strings.Join(formatted, "\n"))
 	}
 
+	connection := challenge.ConnectionInfo
+	if len(challenge.Connections) != 0 {
+		var formatted []string
+
+		for _, c := range challenge.Connections {
+			formatted = append(formatted, fmt.Sprintf("- `%s`", c.Command()))
+		}
+
+		connection = fmt.Sprintf("## Connection:\n\n%s", strings.Join(formatted, "\n"))
+	}
+
 	hints := ""
 	if len(challenge.Hints) != 0 {
 		hints = fmt.Sprintf("\n\n**Hints**: %d\n\n", len(challenge.Hints))
@@ -174,7 +269,31 @@ func formatChallenge(challenge api.Challenge) string {
 
 %s
 
-%s%s`, challenge.Name, challenge.Value, challenge.Category, tags, challenge.Solves, challenge.SolvedByMe, attempts, challenge.Description, challenge.ConnectionInfo, files, hints)
+%s%s`, challenge.Name, challenge.Value, challenge.Category, tags, challenge.Solves, challenge.SolvedByMe, attempts, challenge.Description, connection, files, hints)
+}
+
+func formatNotes(notes string) string {
+	if strings.TrimSpace(notes) == "" {
+		return ""
+	}
+	return fmt.Sprintf("\n\n## Notes:\n\n%s", notes)
+}
+
+func formatHints(hints []api.Hint) string {
+	if len(hints) == 0 {
+		return ""
+	}
+
+	var formatted []string
+	for i, h := range hints {
+		content := h.Content
+		if content == "" {
+			content = fmt.Sprintf("_Locked (cost %d)_", h.Cost)
+		}
+		formatted = append(formatted, fmt.Sprintf("### Hint %d\n\n%s", i+1, content))
+	}
+
+	return fmt.Sprintf("\n\n## Hints:\n\n%s", strings.Join(formatted, "\n\n"))
 }
 
 func (m *challengeModel) setViewportContent() {
@@ -182,9 +301,9 @@ func (m *challengeModel) setViewportContent() {
 	if m.challenge == nil {
 		content = "Loading challenge..."
 	} else {
-		content = formatChallenge(*m.challenge)
+		content = formatChallenge(*m.challenge) + formatNotes(m.notes) + formatClaims(shared.getClaims().Of(m.id)) + formatHints(m.hints)
 	}
-	if str, err := glamour.Render(content, "dark"); err == nil {
+	if str, err := constants.RenderMarkdown(content, 0); err == nil {
 		m.viewport.SetContent(str)
 	} else {
 		m.err = fmt.Errorf("render failed: %v", err)
@@ -193,19 +312,39 @@ func (m *challengeModel) setViewportContent() {
 
 func (m challengeModel) Init() tea.Cmd { return nil }
 
+func (m challengeModel) requests() *requests { return m.reqs }
+
 func (m challengeModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	log.Default().Printf("Challenge view received message: %v, %T\n", msg, msg)
-	var cmd tea.Cmd
+	msg, cmd := m.reqs.accept(msg)
+	if msg == nil {
+		return m, cmd
+	}
+
 	var cmds []tea.Cmd
 	switch msg := msg.(type) {
+	case reloadMsg:
+		cmd = fetchChallengeCmd(m.reqs, m.id)
 	case challengeUpdatedMsg:
+		shared.setChallenge(msg.challenge)
 		m.challenge = msg.challenge
+		m.stale = msg.stale
+		m.reqs.loaded(msg.stale)
+		if m.connection >= len(m.challenge.Connections) {
+			m.connection = 0
+		}
 	case tea.WindowSizeMsg:
 		m.width = msg.Width
 		m.height = msg.Height
 		top, right, bottom, left := constants.DocStyle.GetMargin()
 		m.viewport.Width = m.width - left - right
-		m.viewport.Height = m.height - top - bottom - 5
+		m.viewport.Height = m.height - top - bottom - 6
+	case sessionExpiredMsg:
+		return InitLoginReturning(m, msg.retry, m.width, m.height)
+	case hintsFetchedMsg:
+		m.hints = msg.hints
+	case notesEditedMsg:
+		m.notes, m.err = constants.Notes.Get(msg.id)
 	case messageSetMsg:
 		m.message = msg.message
 	case errMsg:
@@ -215,7 +354,7 @@ func (m challengeModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		if m.input.Focused() {
 			if key.Matches(msg, constants.Keymap.Enter) {
 				if m.mode == submit {
-					cmds = append(cmds, submitFlagCmd(int(m.challenge.Id), m.input.Value()))
+					cmds = append(cmds, submitFlagCmd(m.reqs, int(m.challenge.Id), m.input.Value()))
 				}
 				m.input.SetValue("")
 				m.mode = view
@@ -229,21 +368,23 @@ func (m challengeModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			// only log keypresses for the input field when it's focused
 			m.input, cmd = m.input.Update(msg)
 			cmds = append(cmds, cmd)
-		} else {
-			switch {
-			case key.Matches(msg, ChallengeKeymap.Submit):
-				m.mode = submit
-				m.input.Focus()
-				cmd = textinput.Blink
-			case key.Matches(msg, constants.Keymap.Reload):
-				return m, fetchChallengeCmd(int(m.challenge.Id))
-			case key.Matches(msg, constants.Keymap.Quit):
-				return m, tea.Quit
-			case key.Matches(msg, constants.Keymap.Back):
-				cm, initCmd := InitChallenges(m.width, m.height)
-				return cm, initCmd
+		} else if action, ok := keyAction(msg, m.actions()...); ok {
+			cmd = m.runAction(action)
+		}
+	case actionMsg:
+		cmd = m.runAction(string(msg))
+	case tea.MouseMsg:
+		if msg.Button != tea.MouseButtonLeft {
+			m.viewport, cmd = m.viewport.Update(msg)
+			return m, cmd
+		}
+		_, _, _, left := constants.DocStyle.GetMargin()
+		if msg.Action == tea.MouseActionPress && m.challenge != nil && !m.input.Focused() && msg.Y == m.buttonsLine() {
+			if action, ok := buttonAt(challengeButtons, msg.X-left); ok {
+				return m, m.runAction(action)
 			}
 		}
+		return m, nil
 	}
 
 	m.setViewportContent()
@@ -253,20 +394,133 @@ func (m challengeModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return m, tea.Batch(cmds...)
 }
 
-func (m challengeModel) View() string {
+// challengeButtons are the actions that can be clicked below the description.
+var challengeButtons = []string{constants.ActionSubmit, constants.ActionHints, constants.ActionDownload, constants.ActionNotes, constants.ActionClaim}
+
+func (m challengeModel) buttonEnabled(action string) bool {
+	switch action {
+	case constants.ActionHints:
+		return len(m.challenge.Hints) > 0
+	case constants.ActionDownload:
+		return len(m.challenge.Files) > 0
+	case constants.ActionClaim:
+		return constants.Claims != nil
+	}
+	return true
+}
+
+// buttonsLine is the line of the screen the buttons are drawn on.
+func (m challengeModel) buttonsLine() int {
+	top, _, _, _ := constants.DocStyle.GetMargin()
+	return statusBarHeight + top + 1 + lipgloss.Height(m.viewport.View()) + lipgloss.Height(m.connectionView())
+}
+
+func (m challengeModel) keyContext() string { return "Challenge" }
+
+// typing is whether a flag is being entered.
+func (m challengeModel) typing() bool { return m.input.Focused() }
+
+func (m challengeModel) actions() []string {
+	return []string{
+		constants.ActionSubmit,
+		constants.ActionHints,
+		constants.ActionDownload,
+		constants.ActionNextConnection,
+		constants.ActionCopy,
+		constants.ActionOpen,
+		constants.ActionNotes,
+		constants.ActionClaim,
+		constants.ActionReload,
+		constants.ActionBack,
+		constants.ActionQuit,
+		constants.ActionScoreboard,
+	}
+}
+
+func (m *challengeModel) runAction(action string) tea.Cmd {
+	switch action {
+	case constants.ActionSubmit:
+		if m.challenge != nil {
+			m.mode = submit
+			m.input.Focus()
+			return textinput.Blink
+		}
+	case constants.ActionHints:
+		if m.challenge != nil && len(m.challenge.Hints) > 0 {
+			return fetchHintsCmd(m.reqs, *m.challenge)
+		}
+	case constants.ActionDownload:
+		if m.challenge != nil && len(m.challenge.Files) > 0 {
+			m.message = ""
+			return downloadFilesCmd(m.reqs, *m.challenge)
+		}
+	case constants.ActionNextConnection:
+		if c := m.connections(); len(c) > 0 {
+			m.connection = (m.connection + 1) % len(c)
+		}
+	case constants.ActionCopy:
+		if c, ok := m.selectedConnection(); ok {
+			return copyCmd(c.Command())
+		}
+	case constants.ActionOpen:
+		if c, ok := m.selectedConnection(); ok {
+			return connectionCmd(c)
+		}
+	case constants.ActionNotes:
+		return editNotesCmd(m.id)
+	case constants.ActionClaim:
+		return claimCmd(m.reqs, m.id)
+	case constants.ActionReload:
+		return fetchChallengeCmd(m.reqs, m.id)
+	case constants.ActionQuit:
+		return tea.Quit
+	case constants.ActionBack:
+		return back
+	case constants.ActionScoreboard:
+		return switchTab(scoreboardTab)
+	}
+	return nil
+}
+
+func (m challengeModel) connections() []api.Connection {
 	if m.challenge == nil {
-		return "Loading challenge..."
+		return nil
 	}
+	return m.challenge.Connections
+}
 
+func (m challengeModel) selectedConnection() (api.Connection, bool) {
+	c := m.connections()
+	if m.connection < 0 || m.connection >= len(c) {
+		return api.Connection{}, false
+	}
+	return c[m.connection], true
+}
+
+func (m challengeModel) connectionView() string {
+	c, ok := m.selectedConnection()
+	if !ok {
+		return ""
+	}
+	return fmt.Sprintf("Connection %d/%d: %s", m.connection+1, len(m.connections()), constants.FocusedStyle.Render(c.Command()))
+}
+
+func (m challengeModel) View() string {
 	errStr := renderError(m.err)
 
-	alert := lipgloss.JoinHorizontal(lipgloss.Left, errStr, constants.AlertStyle(m.message))
+	if m.challenge == nil {
+		return statusBarView(m.width, constants.DocStyle.Render(lipgloss.JoinVertical(lipgloss.Top, "", m.reqs.view(), errStr)))
+	}
+
+	alert := lipgloss.JoinHorizontal(lipgloss.Left, errStr, constants.AlertStyle(m.message), renderStale(m.stale), m.reqs.view())
+
+	buttons := buttonsView(challengeButtons, m.buttonEnabled)
 
 	if m.input.Focused() {
-		formatted := lipgloss.JoinVertical(lipgloss.Top, "\n", m.viewport.View(), m.help.View(ChallengeKeymap), alert, m.input.View())
-		return constants.DocStyle.Render(formatted)
+		formatted := lipgloss.JoinVertical(lipgloss.Top, "", m.viewport.View(), m.connectionView(), buttons, m.help.View(ChallengeKeymap), alert, m.input.View())
+		return statusBarView(m.width, constants.DocStyle.Render(formatted))
 	} else {
-		formatted := lipgloss.JoinVertical(lipgloss.Top, "\n", m.viewport.View(), m.help.View(ChallengeKeymap), alert)
-		return constants.DocStyle.Render(formatted)
+		formatted := lipgloss.JoinVertical(lipgloss.Top, "", m.viewport.View(), m.connectionView(), buttons, m.help.View(ChallengeKeymap), alert)
+		return statusBarView(m.width, constants.DocStyle.Render(formatted))
 	}
 }
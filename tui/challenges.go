@@ -5,69 +5,98 @@ import (
 	"fmt"
 	"log"
 	"strconv"
+	"time"
 
 	"github.com/charmbracelet/bubbles/help"
 	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/table"
+	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/jonsth131/ctfd-cli/api"
+	"github.com/jonsth131/ctfd-cli/cache"
 	"github.com/jonsth131/ctfd-cli/tui/constants"
 )
 
 type challengesKeymap struct {
-	Enter  key.Binding
-	Reload key.Binding
-	Quit   key.Binding
+	LineUp     key.Binding
+	LineDown   key.Binding
+	Enter      key.Binding
+	Reload     key.Binding
+	Scoreboard key.Binding
+	Help       key.Binding
+	Quit       key.Binding
 }
 
 func (k challengesKeymap) ShortHelp() []key.Binding {
-	return []key.Binding{k.Enter, k.Reload, k.Quit}
+	return []key.Binding{k.LineUp, k.LineDown, k.Enter, k.Reload, k.Scoreboard, k.Help, k.Quit}
 }
 
 func (k challengesKeymap) FullHelp() [][]key.Binding {
-	return [][]key.Binding{
-		k.ShortHelp(),
-	}
+	return fullHelp("Challenge list")
 }
 
-var ChallengesKeymap = challengesKeymap{
-	Enter:  constants.Keymap.Enter,
-	Reload: constants.Keymap.Reload,
-	Quit:   constants.Keymap.Quit,
+func newChallengesKeymap() challengesKeymap {
+	return challengesKeymap{
+		LineUp:     constants.TableKeyMap.LineUp,
+		LineDown:   constants.TableKeyMap.LineDown,
+		Enter:      constants.Keymap.Enter,
+		Reload:     constants.Keymap.Reload,
+		Scoreboard: constants.ScreensKeymap.Scoreboard,
+		Help:       constants.Keymap.Help,
+		Quit:       constants.Keymap.Quit,
+	}
 }
 
+var ChallengesKeymap = newChallengesKeymap()
+
 type challengesFetchedMsg struct {
 	challenges []api.ListChallenge
+	stale      cache.Status
+}
+
+// splitPaneMinWidth is the terminal width from which the selected challenge
+// is shown next to the list.
+const splitPaneMinWidth = 120
+
+// previewDelay debounces fetching the selected challenge while scrolling.
+const previewDelay = 150 * time.Millisecond
+
+type previewMsg struct {
+	id int
 }
 
 type challengesModel struct {
-	table       table.Model
-	help        help.Model
-	screensHelp help.Model
-	err         error
-	width       int
-	height      int
+	table     table.Model
+	preview   viewport.Model
+	previewId int
+	reqs      *requests
+	help      help.Model
+	err       error
+	stale     cache.Status
+	width     int
+	height    int
 }
 
-func fetchChallengesCmd() tea.Cmd {
-	return func() tea.Msg {
-		ctx, cancel := context.WithTimeout(context.Background(), constants.Timeout)
+func fetchChallengesCmd(r *requests) tea.Cmd {
+	return r.sessionCmd(func(ctx context.Context) tea.Msg {
+		ctx, cancel := context.WithTimeout(ctx, constants.Timeout)
 		defer cancel()
+		ctx, status := cache.WithStatus(ctx)
 		log.Default().Print("Fetching challenges...")
 		challenges, err := constants.C.GetChallenges(ctx)
 		if err != nil {
-			return createErrMsg(fmt.Errorf("Failed to fetch challenges: %v", err))
+			return createErrMsg(fmt.Errorf("Failed to fetch challenges: %w", err))
 		}
 		log.Default().Print("Fetched challenges")
-		return challengesFetchedMsg{challenges}
-	}
+		return challengesFetchedMsg{challenges, *status}
+	})
 }
 
 func setTableSize(t *table.Model, width, height int) {
 	if height != 0 {
 		nameLength := ((width - 17) / 4) * 2
-		categoryLength := width - 30 - nameLength
+		categoryLength := width - 37 - nameLength
 
 		columns := []table.Column{
 			{Title: "ID", Width: 5},
@@ -75,26 +104,41 @@ func setTableSize(t *table.Model, width, height int) {
 			{Title: "Category", Width: categoryLength},
 			{Title: "Value", Width: 5},
 			{Title: "Solved", Width: 7},
+			{Title: "Notes", Width: 5},
+		}
+		// The claims take a third of the name's width.
+		if constants.Claims != nil {
+			claimsLength := nameLength / 3
+			columns[1].Width -= claimsLength + 2
+			columns = append(columns, table.Column{Title: "Claims", Width: claimsLength})
 		}
 
 		t.SetColumns(columns)
 
 		top, right, bottom, left := constants.DocStyle.GetMargin()
 
-		t.SetHeight(height - top - bottom - 5)
+		t.SetHeight(height - top - bottom - 6)
 		t.SetWidth(width - left - right + 1)
 	}
 }
 
 func createRows(challenges []api.ListChallenge) []table.Row {
 	rows := make([]table.Row, len(challenges))
+	board := shared.getClaims()
 
 	for i, challenge := range challenges {
 		solved := ""
 		if challenge.SolvedByMe {
 			solved = "✓"
 		}
-		rows[i] = table.Row{fmt.Sprintf("%d", challenge.Id), challenge.Name, challenge.Category, fmt.Sprintf("%d", challenge.Value), solved}
+		notes := ""
+		if constants.Notes.Has(int(challenge.Id)) {
+			notes = "✎"
+		}
+		rows[i] = table.Row{fmt.Sprintf("%d", challenge.Id), challenge.Name, challenge.Category, fmt.Sprintf("%d", challenge.Value), solved, notes}
+		if constants.Claims != nil {
+			rows[i] = append(rows[i], claimsCell(board.Of(int(challenge.Id))))
+		}
 	}
 
 	return rows
@@ -103,70 +147,222 @@ func createRows(challenges []api.ListChallenge) []table.Row {
 func InitChallenges(width, height int) (tea.Model, tea.Cmd) {
 	t := table.New(
 		table.WithFocused(true),
+		table.WithKeyMap(constants.TableKeyMap),
 	)
 
 	s := constants.TableStyle
 	s.Header = constants.TableHeaderStyle
 	s.Selected = constants.SelectedRowStyle
 	t.SetStyles(s)
-	setTableSize(&t, width, height)
+	t.SetRows(createRows(shared.getChallenges()))
+
+	m := challengesModel{
+		help:    help.New(),
+		table:   t,
+		preview: viewport.New(0, 0),
+		reqs:    newRequests(),
+		width:   width,
+		height:  height,
+	}
+	m.resize()
+
+	return m, tea.Batch(fetchChallengesCmd(m.reqs), m.updatePreview())
+}
 
-	return challengesModel{
-		help:        help.New(),
-		screensHelp: help.New(),
-		table:       t,
-		width:       width,
-		height:      height,
-	}, tea.Batch(fetchChallengesCmd())
+func (m challengesModel) split() bool {
+	return m.width >= splitPaneMinWidth
+}
+
+// resize lays out the list alone or, on wide terminals, the list on the left
+// and the preview on the right.
+func (m *challengesModel) resize() {
+	if !m.split() {
+		setTableSize(&m.table, m.width, m.height)
+		return
+	}
+
+	listWidth := m.width * 2 / 5
+	setTableSize(&m.table, listWidth, m.height)
+
+	// Both panes have a border of one column on each side.
+	m.preview.Width = m.width - m.table.Width() - 4
+	m.preview.Height = m.table.Height()
+	m.renderPreview()
+}
+
+func (m challengesModel) selectedId() int {
+	curr := m.table.SelectedRow()
+	if curr == nil {
+		return 0
+	}
+	id, _ := strconv.Atoi(curr[0])
+	return id
+}
+
+// updatePreview shows the selected challenge, fetching it after a short delay
+// if it hasn't been loaded yet.
+func (m *challengesModel) updatePreview() tea.Cmd {
+	id := m.selectedId()
+	if !m.split() || id == m.previewId {
+		return nil
+	}
+	m.previewId = id
+	m.renderPreview()
+
+	if id == 0 || shared.getChallenge(id) != nil {
+		return nil
+	}
+	return tea.Tick(previewDelay, func(time.Time) tea.Msg { return previewMsg{id} })
+}
+
+func (m *challengesModel) renderPreview() {
+	if !m.split() {
+		return
+	}
+
+	content := "Loading challenge..."
+	if m.previewId == 0 {
+		content = ""
+	} else if c := shared.getChallenge(m.previewId); c != nil {
+		notes, _ := constants.Notes.Get(m.previewId)
+		content = formatChallenge(*c) + formatNotes(notes) + formatClaims(shared.getClaims().Of(m.previewId))
+	}
+
+	content, err := constants.RenderMarkdown(content, m.preview.Width-2)
+	if err != nil {
+		m.err = fmt.Errorf("render failed: %v", err)
+		return
+	}
+	m.preview.SetContent(content)
+	m.preview.GotoTop()
 }
 
 func (m challengesModel) Init() tea.Cmd { return nil }
 
+func (m challengesModel) requests() *requests { return m.reqs }
+
 func (m challengesModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	log.Default().Printf("Challenges view received message: %v, %T\n", msg, msg)
+	msg, cmd := m.reqs.accept(msg)
+	if msg == nil {
+		return m, cmd
+	}
+
 	switch msg := msg.(type) {
+	case reloadMsg:
+		return m, fetchChallengesCmd(m.reqs)
 	case challengesFetchedMsg:
+		shared.setChallenges(msg.challenges)
 		m.table.SetRows(createRows(msg.challenges))
+		m.stale = msg.stale
+		m.reqs.loaded(msg.stale)
+		if msg.stale.Stale && isForbidden(msg.stale.Err) {
+			return m, fetchCTFStatusCmd(m.reqs)
+		}
+		return m, m.updatePreview()
+	case previewMsg:
+		if msg.id != m.previewId {
+			return m, nil
+		}
+		return m, fetchChallengeCmd(m.reqs, msg.id)
+	case challengeUpdatedMsg:
+		shared.setChallenge(msg.challenge)
+		if int(msg.challenge.Id) == m.previewId {
+			m.renderPreview()
+		}
+		return m, nil
+	case claimsUpdatedMsg:
+		// Rows can't be rendered before the columns are laid out.
+		if len(m.table.Columns()) > 0 {
+			m.table.SetRows(createRows(shared.getChallenges()))
+			m.renderPreview()
+		}
+		return m, nil
+	case ctfStatusMsg:
+		if msg.status.State != api.CTFRunning {
+			return InitWait(msg.status, m.width, m.height)
+		}
 		return m, nil
 	case tea.KeyMsg:
-		switch {
-		case key.Matches(msg, constants.Keymap.Reload):
-			m.err = nil
-			return m, fetchChallengesCmd()
-		case key.Matches(msg, constants.Keymap.Quit):
-			return m, tea.Quit
-		case key.Matches(msg, constants.Keymap.Enter):
-			curr := m.table.SelectedRow()
-			if curr == nil {
-				return m, nil
-			}
-			id, _ := strconv.Atoi(curr[0])
-			challenge, initCmd := InitChallenge(id, m.width, m.height)
-			return challenge, initCmd
-		case key.Matches(msg, constants.ScreensKeymap.Scoreboard):
-			sbm, initCmd := InitScoreboard(m.width, m.height)
-			return sbm, initCmd
+		if action, ok := keyAction(msg, m.actions()...); ok {
+			return m, m.runAction(action)
 		}
+	case actionMsg:
+		return m, m.runAction(string(msg))
+	case tea.MouseMsg:
+		// The preview is drawn in a border right of the table's.
+		if m.split() && msg.X >= lipgloss.Width(m.table.View())+2 {
+			m.preview, cmd = m.preview.Update(msg)
+			return m, cmd
+		}
+		// The table is drawn in a border below the status bar, and clicking
+		// the selected challenge opens it.
+		if clickTable(&m.table, msg, statusBarHeight+1, 1) {
+			return m, m.runAction(constants.ActionEnter)
+		}
+		return m, m.updatePreview()
 	case tea.WindowSizeMsg:
 		m.width = msg.Width
 		m.height = msg.Height
-		setTableSize(&m.table, m.width, m.height)
-		return m, nil
+		m.previewId = 0
+		m.resize()
+		// The screen is resized when shown again, after notes may have been
+		// edited on the challenge screen.
+		m.table.SetRows(createRows(shared.getChallenges()))
+		return m, m.updatePreview()
+	case sessionExpiredMsg:
+		return InitLoginReturning(m, msg.retry, m.width, m.height)
 	case errMsg:
 		log.Default().Print(msg)
 		m.err = msg
+		// The challenges are forbidden while the CTF isn't running.
+		if isForbidden(msg) {
+			return m, fetchCTFStatusCmd(m.reqs)
+		}
 	}
 	cmds := make([]tea.Cmd, 2)
 	m.table, cmds[0] = m.table.Update(msg)
+	cmds[1] = m.updatePreview()
 
 	return m, tea.Batch(cmds...)
 }
 
+func (m challengesModel) keyContext() string { return "Challenge list" }
+
+func (m challengesModel) actions() []string {
+	return []string{constants.ActionEnter, constants.ActionReload, constants.ActionClaim, constants.ActionQuit, constants.ActionScoreboard}
+}
+
+func (m *challengesModel) runAction(action string) tea.Cmd {
+	switch action {
+	case constants.ActionReload:
+		m.err = nil
+		return fetchChallengesCmd(m.reqs)
+	case constants.ActionQuit:
+		return tea.Quit
+	case constants.ActionEnter:
+		if id := m.selectedId(); id != 0 {
+			return navigate(InitChallenge(id, m.width, m.height))
+		}
+	case constants.ActionClaim:
+		if id := m.selectedId(); id != 0 {
+			return claimCmd(m.reqs, id)
+		}
+	case constants.ActionScoreboard:
+		return switchTab(scoreboardTab)
+	}
+	return nil
+}
+
 func (m challengesModel) View() string {
-	helpText := lipgloss.JoinHorizontal(lipgloss.Top, constants.HelpStyle(m.table.HelpView()), constants.HelpStyle(" • "), constants.HelpStyle(m.help.View(ChallengesKeymap)))
-	screensHelpText := lipgloss.JoinHorizontal(lipgloss.Top, constants.HelpStyle(m.screensHelp.View(constants.ScreensKeymap)))
+	helpText := m.help.View(ChallengesKeymap)
 	errStr := renderError(m.err)
 
-	return lipgloss.JoinVertical(lipgloss.Top, constants.BaseStyle.Render(m.table.View()),
-		screensHelpText, helpText, errStr)
+	panes := constants.BaseStyle.Render(m.table.View())
+	if m.split() {
+		panes = lipgloss.JoinHorizontal(lipgloss.Top, panes, constants.BaseStyle.Render(m.preview.View()))
+	}
+
+	return statusBarView(m.width, lipgloss.JoinVertical(lipgloss.Top, panes,
+		helpText, m.reqs.view(), renderStale(m.stale), errStr))
 }
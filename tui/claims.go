@@ -0,0 +1,111 @@
+package tui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/jonsth131/ctfd-cli/claims"
+	"github.com/jonsth131/ctfd-cli/tui/constants"
+)
+
+// claimsRetryInterval is how long to wait before asking the claim board
+// again after a failure.
+const claimsRetryInterval = 10 * time.Second
+
+// claimsUpdatedMsg tells the screens that the claim board changed.
+type claimsUpdatedMsg struct{}
+
+// runClaims waits for changes of the claim board until ctx is done, keeping
+// the shared board up to date.
+func runClaims(ctx context.Context, p *tea.Program) {
+	// No board has this version, so the first request returns right away.
+	version := -1
+	for {
+		board, err := constants.Claims.Wait(ctx, version)
+		if ctx.Err() != nil {
+			return
+		}
+		if err != nil {
+			log.Default().Printf("Claims: %v", err)
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(claimsRetryInterval):
+			}
+			continue
+		}
+
+		version = board.Version
+		shared.setClaims(board)
+		p.Send(claimsUpdatedMsg{})
+	}
+}
+
+// claimUser is the name the team sees on the claims.
+func claimUser() string {
+	switch {
+	case constants.Profile.Claims.User != "":
+		return constants.Profile.Claims.User
+	case constants.Profile.User != "":
+		return constants.Profile.User
+	}
+	return currentStatus().user
+}
+
+// claimCmd moves the claim of the user on a challenge to the next status,
+// clearing it after the last.
+func claimCmd(r *requests, id int) tea.Cmd {
+	return r.cmd(func(ctx context.Context) tea.Msg {
+		if constants.Claims == nil {
+			return createErrMsg(errors.New("No claim board configured, see claims in the README"))
+		}
+		user := claimUser()
+		if user == "" {
+			return createErrMsg(errors.New("Unknown user for claims, set the user of the claims in the profile"))
+		}
+
+		ctx, cancel := context.WithTimeout(ctx, constants.Timeout)
+		defer cancel()
+
+		var board claims.Board
+		var err error
+		current, _ := shared.getClaims().Find(id, user)
+		if next, ok := claims.Next(current.Status); ok {
+			board, err = constants.Claims.Set(ctx, id, user, next)
+		} else {
+			board, err = constants.Claims.Clear(ctx, id, user)
+		}
+		if err != nil {
+			return createErrMsg(fmt.Errorf("Failed to claim challenge %d: %w", id, err))
+		}
+
+		shared.setClaims(board)
+		return claimsUpdatedMsg{}
+	})
+}
+
+// claimsCell shows the claims on a challenge in the challenge list.
+func claimsCell(list []claims.Claim) string {
+	parts := make([]string, len(list))
+	for i, c := range list {
+		parts[i] = c.String()
+	}
+	return strings.Join(parts, ", ")
+}
+
+func formatClaims(list []claims.Claim) string {
+	if len(list) == 0 {
+		return ""
+	}
+
+	lines := make([]string, len(list))
+	for i, c := range list {
+		lines[i] = fmt.Sprintf("- **%s** %s since %s", c.User, c.Status, c.Updated.Local().Format("15:04"))
+	}
+	return fmt.Sprintf("\n\n## Claims:\n\n%s", strings.Join(lines, "\n"))
+}
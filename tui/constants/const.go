@@ -8,39 +8,64 @@ import (
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/jonsth131/ctfd-cli/api"
+	"github.com/jonsth131/ctfd-cli/claims"
+	"github.com/jonsth131/ctfd-cli/config"
+	"github.com/jonsth131/ctfd-cli/hooks"
+	"github.com/jonsth131/ctfd-cli/notes"
 )
 
 var (
-	P *tea.Program
-	C api.CTFdAPI
+	P      *tea.Program
+	C      api.CTFdAPI
+	Config *config.Config
+	// Profile is the selected profile from Config, saved to ConfigPath as
+	// ProfileName.
+	Profile     config.Profile
+	ConfigPath  string
+	ProfileName string
+	// Notes holds the notes of the profile on the challenges.
+	Notes *notes.Store
+	// Claims is the team's claim board, nil if the profile has none.
+	Claims *claims.Client
+	// Hooks runs the hooks of Config on events seen by the status bar.
+	Hooks *hooks.Runner
 	// WindowSize tea.WindowSizeMsg
 )
 
-const (
-	Timeout = 5 * time.Second
-)
+// Timeout bounds a single API call including retries. It is derived from
+// the api.Options the client was created with.
+var Timeout = 5 * time.Second
 
+// The styles are set from the theme, see ApplyTheme.
 var (
-	DocStyle         = lipgloss.NewStyle().Margin(0, 2)
-	HelpStyle        = lipgloss.NewStyle().Foreground(lipgloss.Color("241")).Render
-	ErrStyle         = lipgloss.NewStyle().Foreground(lipgloss.Color("#bd534b")).Render
-	AlertStyle       = lipgloss.NewStyle().Foreground(lipgloss.Color("62")).Render
-	BaseStyle        = lipgloss.NewStyle().BorderStyle(lipgloss.NormalBorder()).BorderForeground(lipgloss.Color("240"))
-	TableStyle       = table.DefaultStyles()
-	TableHeaderStyle = lipgloss.NewStyle().BorderStyle(lipgloss.NormalBorder()).BorderForeground(lipgloss.Color("240")).BorderBottom(true).Bold(false)
-	SelectedRowStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("229")).Background(lipgloss.Color("57")).Bold(false)
-	FocusedStyle     = lipgloss.NewStyle().Foreground(lipgloss.Color("205"))
-	BlurredStyle     = lipgloss.NewStyle().Foreground(lipgloss.Color("240"))
-	CursorStyle      = FocusedStyle
-	NoStyle          = lipgloss.NewStyle()
-	SpinnerStyle     = FocusedStyle
+	DocStyle             = lipgloss.NewStyle().Margin(0, 2)
+	HelpStyle            func(...string) string
+	ErrStyle             func(...string) string
+	AlertStyle           func(...string) string
+	BaseStyle            lipgloss.Style
+	TableStyle           = table.DefaultStyles()
+	TableHeaderStyle     lipgloss.Style
+	SelectedRowStyle     lipgloss.Style
+	FocusedStyle         lipgloss.Style
+	BlurredStyle         lipgloss.Style
+	CursorStyle          lipgloss.Style
+	NoStyle              = lipgloss.NewStyle()
+	SpinnerStyle         lipgloss.Style
+	ProgressStyle        lipgloss.Style
+	ProgressEmptyStyle   lipgloss.Style
+	StatusBarStyle       lipgloss.Style
+	StatusBarAccentStyle lipgloss.Style
 )
 
 type keymap struct {
-	Enter  key.Binding
-	Back   key.Binding
-	Reload key.Binding
-	Quit   key.Binding
+	Enter          key.Binding
+	Back           key.Binding
+	Reload         key.Binding
+	Quit           key.Binding
+	HistoryBack    key.Binding
+	HistoryForward key.Binding
+	Palette        key.Binding
+	Help           key.Binding
 }
 
 func (k keymap) ShortHelp() []key.Binding {
@@ -49,29 +74,27 @@ func (k keymap) ShortHelp() []key.Binding {
 
 func (k keymap) FullHelp() [][]key.Binding {
 	return [][]key.Binding{
-		k.ShortHelp(),
+		{k.Quit, k.Palette, k.Help},
+		{k.Back, k.HistoryBack, k.HistoryForward},
+		{k.Enter, k.Reload},
 	}
 }
 
-var Keymap = keymap{
-	Enter: key.NewBinding(
-		key.WithKeys("enter"),
-		key.WithHelp("enter", "select"),
-	),
-	Back: key.NewBinding(
-		key.WithKeys("esc"),
-		key.WithHelp("esc", "back"),
-	),
-	Reload: key.NewBinding(
-		key.WithKeys("r"),
-		key.WithHelp("r", "reload"),
-	),
-	Quit: key.NewBinding(
-		key.WithKeys("ctrl+c", "q"),
-		key.WithHelp("ctrl+c/q", "quit"),
-	),
+func newKeymap() keymap {
+	return keymap{
+		Enter:          Binding(ActionEnter),
+		Back:           Binding(ActionBack),
+		Reload:         Binding(ActionReload),
+		Quit:           Binding(ActionQuit),
+		HistoryBack:    Binding(ActionHistoryBack),
+		HistoryForward: Binding(ActionHistoryForward),
+		Palette:        Binding(ActionPalette),
+		Help:           Binding(ActionShowHelp),
+	}
 }
 
+var Keymap = newKeymap()
+
 type screensKeymap struct {
 	Challenges key.Binding
 	Scoreboard key.Binding
@@ -87,13 +110,11 @@ func (k screensKeymap) FullHelp() [][]key.Binding {
 	}
 }
 
-var ScreensKeymap = screensKeymap{
-	Challenges: key.NewBinding(
-		key.WithKeys("1"),
-		key.WithHelp("1", "challenges"),
-	),
-	Scoreboard: key.NewBinding(
-		key.WithKeys("2"),
-		key.WithHelp("2", "scoreboard"),
-	),
+func newScreensKeymap() screensKeymap {
+	return screensKeymap{
+		Challenges: Binding(ActionChallenges),
+		Scoreboard: Binding(ActionScoreboard),
+	}
 }
+
+var ScreensKeymap = newScreensKeymap()
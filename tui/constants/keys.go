@@ -0,0 +1,324 @@
+package constants
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/table"
+	"github.com/jonsth131/ctfd-cli/config"
+)
+
+// Actions that can be bound to keys in the keys section of the config.
+const (
+	ActionQuit           = "quit"
+	ActionHistoryBack    = "history_back"
+	ActionHistoryForward = "history_forward"
+	ActionChallenges     = "challenges"
+	ActionScoreboard     = "scoreboard"
+	ActionPalette        = "palette"
+	ActionShowHelp       = "help"
+	ActionEnter          = "enter"
+	ActionBack           = "back"
+	ActionReload         = "reload"
+	ActionSubmit         = "submit"
+	ActionHints          = "hints"
+	ActionDownload       = "download"
+	ActionNextConnection = "next_connection"
+	ActionCopy           = "copy"
+	ActionOpen           = "open"
+	ActionNotes          = "notes"
+	ActionClaim          = "claim"
+	ActionLineUp         = "line_up"
+	ActionLineDown       = "line_down"
+	ActionPageUp         = "page_up"
+	ActionPageDown       = "page_down"
+	ActionHalfPageUp     = "half_page_up"
+	ActionHalfPageDown   = "half_page_down"
+	ActionGotoTop        = "goto_top"
+	ActionGotoBottom     = "goto_bottom"
+)
+
+const DefaultPreset = "default"
+
+// Bindings maps actions to the keys bound to them.
+type Bindings map[string][]string
+
+var actionHelp = map[string]string{
+	ActionQuit:           "quit",
+	ActionHistoryBack:    "previous screen",
+	ActionHistoryForward: "next screen",
+	ActionChallenges:     "challenges",
+	ActionScoreboard:     "scoreboard",
+	ActionPalette:        "command palette",
+	ActionShowHelp:       "toggle help",
+	ActionEnter:          "select",
+	ActionBack:           "back",
+	ActionReload:         "reload",
+	ActionSubmit:         "submit flag",
+	ActionHints:          "hints",
+	ActionDownload:       "download files",
+	ActionNextConnection: "next connection",
+	ActionCopy:           "copy connection",
+	ActionOpen:           "open connection",
+	ActionNotes:          "edit notes",
+	ActionClaim:          "cycle claim",
+	ActionLineUp:         "up",
+	ActionLineDown:       "down",
+	ActionPageUp:         "page up",
+	ActionPageDown:       "page down",
+	ActionHalfPageUp:     "½ page up",
+	ActionHalfPageDown:   "½ page down",
+	ActionGotoTop:        "go to start",
+	ActionGotoBottom:     "go to end",
+}
+
+// TableActions move through the challenge list and the scoreboard.
+var TableActions = []string{ActionLineUp, ActionLineDown, ActionPageUp, ActionPageDown, ActionHalfPageUp, ActionHalfPageDown, ActionGotoTop, ActionGotoBottom}
+
+// KeyContext is a group of actions that are active at the same time, so
+// they must not share keys. The global actions are active everywhere. Keys
+// are used by the screens of the context and can't be bound to actions.
+type KeyContext struct {
+	Name    string
+	Actions []string
+	Keys    []string
+}
+
+var KeyContexts = []KeyContext{
+	{"Global", []string{ActionQuit, ActionHistoryBack, ActionHistoryForward, ActionChallenges, ActionScoreboard, ActionPalette, ActionShowHelp}, nil},
+	{"Challenge list", append([]string{ActionEnter, ActionReload, ActionClaim}, TableActions...), nil},
+	{"Scoreboard", append([]string{ActionReload}, TableActions...), nil},
+	{"Challenge", []string{ActionBack, ActionReload, ActionSubmit, ActionHints, ActionDownload, ActionNextConnection, ActionCopy, ActionOpen, ActionNotes, ActionClaim}, nil},
+	// The login, register and team forms move between their fields.
+	{"Form", nil, []string{"tab", "shift+tab", "up", "down", "enter"}},
+}
+
+var defaultBindings = Bindings{
+	ActionQuit:           {"ctrl+c", "q"},
+	ActionHistoryBack:    {"alt+left"},
+	ActionHistoryForward: {"alt+right"},
+	ActionChallenges:     {"1"},
+	ActionScoreboard:     {"2"},
+	ActionPalette:        {"ctrl+p"},
+	ActionShowHelp:       {"?"},
+	ActionEnter:          {"enter"},
+	ActionBack:           {"esc"},
+	ActionReload:         {"r"},
+	ActionSubmit:         {"s"},
+	ActionHints:          {"h"},
+	ActionDownload:       {"d"},
+	ActionNextConnection: {"tab"},
+	ActionCopy:           {"c"},
+	ActionOpen:           {"o"},
+	ActionNotes:          {"e"},
+	ActionClaim:          {"w"},
+	ActionLineUp:         {"up", "k"},
+	ActionLineDown:       {"down", "j"},
+	ActionPageUp:         {"b", "pgup"},
+	ActionPageDown:       {"f", "pgdown", " "},
+	ActionHalfPageUp:     {"u", "ctrl+u"},
+	ActionHalfPageDown:   {"d", "ctrl+d"},
+	ActionGotoTop:        {"home", "g"},
+	ActionGotoBottom:     {"end", "G"},
+}
+
+// presets only list the actions they bind differently from the default.
+var presets = map[string]Bindings{
+	DefaultPreset: {},
+	"vim": {
+		// Terminals send ctrl+i as tab, which the forms need, so
+		// history_forward keeps alt+right.
+		ActionHistoryBack:    {"ctrl+o"},
+		ActionEnter:          {"enter", "l"},
+		ActionBack:           {"esc", "backspace"},
+		ActionNextConnection: {"n"},
+		ActionPageUp:         {"ctrl+b", "pgup"},
+		ActionPageDown:       {"ctrl+f", "pgdown"},
+	},
+	"emacs": {
+		ActionQuit:         {"ctrl+c"},
+		ActionChallenges:   {"alt+1"},
+		ActionScoreboard:   {"alt+2"},
+		ActionPalette:      {"alt+x"},
+		ActionBack:         {"ctrl+g", "esc"},
+		ActionReload:       {"g"},
+		ActionSubmit:       {"alt+s"},
+		ActionHints:        {"alt+h"},
+		ActionDownload:     {"alt+d"},
+		ActionCopy:         {"alt+w"},
+		ActionOpen:         {"alt+o"},
+		ActionNotes:        {"alt+e"},
+		ActionLineUp:       {"up", "ctrl+p"},
+		ActionLineDown:     {"down", "ctrl+n"},
+		ActionPageUp:       {"pgup", "alt+v"},
+		ActionPageDown:     {"pgdown", "ctrl+v"},
+		ActionHalfPageUp:   {},
+		ActionHalfPageDown: {},
+		ActionGotoTop:      {"home", "alt+<"},
+		ActionGotoBottom:   {"end", "alt+>"},
+	},
+}
+
+// Presets returns the names of the presets.
+func Presets() []string {
+	names := make([]string, 0, len(presets))
+	for name := range presets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+var (
+	bindings = defaultBindings
+	// custom holds the actions bound in the config rather than by a preset.
+	custom = map[string]bool{}
+)
+
+// LoadKeymap applies the preset and the bindings from the config and
+// rebuilds the keymaps. An unknown preset or action, or actions sharing a
+// key where they are active at the same time, are errors.
+func LoadKeymap(cfg config.Keys) error {
+	b, err := ResolveBindings(cfg)
+	if err != nil {
+		return err
+	}
+
+	bindings = b
+	custom = map[string]bool{}
+	for action := range cfg.Bindings {
+		custom[action] = true
+	}
+	Keymap = newKeymap()
+	ScreensKeymap = newScreensKeymap()
+	TableKeyMap = newTableKeyMap()
+	return nil
+}
+
+// ResolveBindings merges the preset and the bindings from the config over
+// the default bindings and checks them for conflicts.
+func ResolveBindings(cfg config.Keys) (Bindings, error) {
+	preset := cfg.Preset
+	if preset == "" {
+		preset = DefaultPreset
+	}
+	overrides, ok := presets[preset]
+	if !ok {
+		return nil, fmt.Errorf("unknown key preset %q, expected one of %s", preset, strings.Join(Presets(), ", "))
+	}
+
+	b := Bindings{}
+	for action, keys := range defaultBindings {
+		b[action] = keys
+	}
+	for action, keys := range overrides {
+		b[action] = keys
+	}
+	for action, keys := range cfg.Bindings {
+		if _, ok := actionHelp[action]; !ok {
+			return nil, fmt.Errorf("unknown action %q in key bindings", action)
+		}
+		b[action] = keys
+	}
+
+	if err := checkConflicts(b); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+func checkConflicts(b Bindings) error {
+	global := KeyContexts[0]
+	for _, c := range KeyContexts {
+		actions := c.Actions
+		if c.Name != global.Name {
+			actions = append(slices.Clone(global.Actions), actions...)
+		}
+
+		bound := map[string]string{}
+		for _, k := range c.Keys {
+			bound[k] = "the " + strings.ToLower(c.Name) + " screens"
+		}
+		for _, action := range actions {
+			for _, k := range b[action] {
+				if other, ok := bound[k]; ok && other != action {
+					return fmt.Errorf("key %q is bound to both %s and %s in the %s context", k, other, action, strings.ToLower(c.Name))
+				}
+				bound[k] = action
+			}
+		}
+	}
+	return nil
+}
+
+// ActionHelp describes what an action does.
+func ActionHelp(action string) string {
+	return actionHelp[action]
+}
+
+// IsCustom reports whether the keys of action were set in the config.
+func IsCustom(action string) bool {
+	return custom[action]
+}
+
+// KeyNames lists all keys bound to action.
+func KeyNames(action string) []string {
+	names := make([]string, len(bindings[action]))
+	for i, k := range bindings[action] {
+		names[i] = KeyName(k)
+	}
+	return names
+}
+
+// Binding returns the binding of action with help listing its keys.
+func Binding(action string) key.Binding {
+	keys := bindings[action]
+	if len(keys) == 0 {
+		return key.NewBinding(key.WithDisabled())
+	}
+
+	shown := make([]string, 0, 2)
+	for _, k := range keys[:min(len(keys), 2)] {
+		shown = append(shown, KeyName(k))
+	}
+	return key.NewBinding(
+		key.WithKeys(keys...),
+		key.WithHelp(strings.Join(shown, "/"), actionHelp[action]),
+	)
+}
+
+// KeyName is how a key is shown in help.
+func KeyName(k string) string {
+	switch k {
+	case " ":
+		return "space"
+	case "pgup":
+		return k
+	case "pgdown":
+		return "pgdn"
+	}
+	r := strings.NewReplacer("left", "←", "right", "→", "up", "↑", "down", "↓")
+	if prefix, rest, ok := strings.Cut(k, "+"); ok {
+		return prefix + "+" + r.Replace(rest)
+	}
+	return r.Replace(k)
+}
+
+func newTableKeyMap() table.KeyMap {
+	return table.KeyMap{
+		LineUp:       Binding(ActionLineUp),
+		LineDown:     Binding(ActionLineDown),
+		PageUp:       Binding(ActionPageUp),
+		PageDown:     Binding(ActionPageDown),
+		HalfPageUp:   Binding(ActionHalfPageUp),
+		HalfPageDown: Binding(ActionHalfPageDown),
+		GotoTop:      Binding(ActionGotoTop),
+		GotoBottom:   Binding(ActionGotoBottom),
+	}
+}
+
+// TableKeyMap moves through the challenge list and the scoreboard.
+var TableKeyMap = newTableKeyMap()
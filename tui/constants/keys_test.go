@@ -0,0 +1,82 @@
+package constants
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/jonsth131/ctfd-cli/config"
+)
+
+func TestPresetsHaveNoConflicts(t *testing.T) {
+	for _, preset := range Presets() {
+		t.Run(preset, func(t *testing.T) {
+			if _, err := ResolveBindings(config.Keys{Preset: preset}); err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestResolveBindings(t *testing.T) {
+	b, err := ResolveBindings(config.Keys{
+		Preset:   "vim",
+		Bindings: map[string][]string{ActionHints: {}, ActionQuit: {"ctrl+c"}},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		action   string
+		expected []string
+	}{
+		// From the preset.
+		{ActionHistoryBack, []string{"ctrl+o"}},
+		{ActionNextConnection, []string{"n"}},
+		// From the default.
+		{ActionReload, []string{"r"}},
+		// From the config.
+		{ActionHints, []string{}},
+		{ActionQuit, []string{"ctrl+c"}},
+	}
+	for _, test := range tests {
+		if keys := b[test.action]; !slices.Equal(keys, test.expected) {
+			t.Errorf("%s is bound to %q, want %q", test.action, keys, test.expected)
+		}
+	}
+}
+
+func TestResolveBindingsErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		keys     config.Keys
+		errorMsg string
+	}{
+		{"Unknown preset", config.Keys{Preset: "nano"}, `unknown key preset "nano"`},
+		{"Unknown action", config.Keys{Bindings: map[string][]string{"fly": {"x"}}}, `unknown action "fly"`},
+		{"Same screen", config.Keys{Bindings: map[string][]string{ActionHints: {"s"}}}, "in the challenge context"},
+		{"Global and screen", config.Keys{Bindings: map[string][]string{ActionScoreboard: {"r"}}}, `key "r" is bound to both`},
+		{"Form keys", config.Keys{Bindings: map[string][]string{ActionHistoryBack: {"shift+tab"}}}, "in the form context"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := ResolveBindings(test.keys)
+			if err == nil {
+				t.Fatalf("expected error, got nil")
+			}
+			if !strings.Contains(err.Error(), test.errorMsg) {
+				t.Errorf("expected error containing %q, got %q", test.errorMsg, err.Error())
+			}
+		})
+	}
+}
+
+func TestDifferentContextsMayShareKeys(t *testing.T) {
+	// The challenge list and the challenge are never active at the same time.
+	keys := config.Keys{Bindings: map[string][]string{ActionEnter: {"s"}}}
+	if _, err := ResolveBindings(keys); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,185 @@
+package constants
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/glamour"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/jonsth131/ctfd-cli/config"
+)
+
+const (
+	AutoTheme    = "auto"
+	DarkTheme    = "dark"
+	NoColorTheme = "no-color"
+)
+
+// themes are the built-in themes. Without colors, the selection and the
+// status bar are shown in reverse video and focused elements in bold.
+var themes = map[string]config.Theme{
+	DarkTheme: {
+		Muted:      "241",
+		Border:     "240",
+		Accent:     "205",
+		Alert:      "62",
+		Error:      "#bd534b",
+		SelectedFg: "229",
+		SelectedBg: "57",
+		StatusFg:   "252",
+		StatusBg:   "236",
+		Markdown:   "dark",
+	},
+	"light": {
+		Muted:      "244",
+		Border:     "250",
+		Accent:     "162",
+		Alert:      "25",
+		Error:      "#a3322a",
+		SelectedFg: "231",
+		SelectedBg: "57",
+		StatusFg:   "235",
+		StatusBg:   "254",
+		Markdown:   "light",
+	},
+	"high-contrast": {
+		Muted:      "15",
+		Border:     "15",
+		Accent:     "11",
+		Alert:      "14",
+		Error:      "9",
+		SelectedFg: "0",
+		SelectedBg: "11",
+		StatusFg:   "0",
+		StatusBg:   "15",
+		Markdown:   "dark",
+	},
+	NoColorTheme: {
+		Markdown: "notty",
+	},
+}
+
+// MarkdownStyle is the glamour style of the theme.
+var MarkdownStyle string
+
+func init() {
+	setStyles(themes[DarkTheme])
+}
+
+// Themes returns the names of the built-in themes.
+func Themes() []string {
+	names := make([]string, 0, len(themes))
+	for name := range themes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ApplyTheme sets the styles from the theme with the given name, looked up
+// in custom before the built-in themes. The auto theme picks dark or light
+// from the background of the terminal. NO_COLOR overrides the theme.
+func ApplyTheme(name string, custom map[string]config.Theme) error {
+	theme, err := ResolveTheme(name, custom)
+	if err != nil {
+		return err
+	}
+	if os.Getenv("NO_COLOR") != "" {
+		theme = themes[NoColorTheme]
+	}
+	if _, err := glamour.NewTermRenderer(glamour.WithStylePath(theme.Markdown)); err != nil {
+		return fmt.Errorf("invalid markdown style %q: %w", theme.Markdown, err)
+	}
+	setStyles(theme)
+	return nil
+}
+
+// ResolveTheme looks up a theme and fills in the colors it leaves empty from
+// its base.
+func ResolveTheme(name string, custom map[string]config.Theme) (config.Theme, error) {
+	if name == "" || name == AutoTheme {
+		name = DarkTheme
+		if !lipgloss.HasDarkBackground() {
+			name = "light"
+		}
+	}
+
+	if theme, ok := custom[name]; ok {
+		base := theme.Base
+		if base == "" {
+			base = DarkTheme
+		}
+		b, ok := themes[base]
+		if !ok {
+			return config.Theme{}, fmt.Errorf("unknown base theme %q for theme %q, expected one of %s", base, name, strings.Join(Themes(), ", "))
+		}
+		return mergeTheme(b, theme), nil
+	}
+
+	theme, ok := themes[name]
+	if !ok {
+		return config.Theme{}, fmt.Errorf("unknown theme %q, expected one of %s or a theme from the config", name, strings.Join(Themes(), ", "))
+	}
+	return theme, nil
+}
+
+func mergeTheme(base, t config.Theme) config.Theme {
+	pick := func(s, fallback string) string {
+		if s == "" {
+			return fallback
+		}
+		return s
+	}
+	return config.Theme{
+		Muted:      pick(t.Muted, base.Muted),
+		Border:     pick(t.Border, base.Border),
+		Accent:     pick(t.Accent, base.Accent),
+		Alert:      pick(t.Alert, base.Alert),
+		Error:      pick(t.Error, base.Error),
+		SelectedFg: pick(t.SelectedFg, base.SelectedFg),
+		SelectedBg: pick(t.SelectedBg, base.SelectedBg),
+		StatusFg:   pick(t.StatusFg, base.StatusFg),
+		StatusBg:   pick(t.StatusBg, base.StatusBg),
+		Markdown:   pick(t.Markdown, base.Markdown),
+	}
+}
+
+func setStyles(t config.Theme) {
+	color := func(c string) lipgloss.TerminalColor {
+		if c == "" {
+			return lipgloss.NoColor{}
+		}
+		return lipgloss.Color(c)
+	}
+
+	HelpStyle = lipgloss.NewStyle().Foreground(color(t.Muted)).Render
+	ErrStyle = lipgloss.NewStyle().Foreground(color(t.Error)).Render
+	AlertStyle = lipgloss.NewStyle().Foreground(color(t.Alert)).Render
+	BaseStyle = lipgloss.NewStyle().BorderStyle(lipgloss.NormalBorder()).BorderForeground(color(t.Border))
+	TableHeaderStyle = lipgloss.NewStyle().BorderStyle(lipgloss.NormalBorder()).BorderForeground(color(t.Border)).BorderBottom(true).Bold(false)
+	SelectedRowStyle = lipgloss.NewStyle().Foreground(color(t.SelectedFg)).Background(color(t.SelectedBg)).Bold(false).Reverse(t.SelectedBg == "")
+	FocusedStyle = lipgloss.NewStyle().Foreground(color(t.Accent)).Bold(t.Accent == "")
+	BlurredStyle = lipgloss.NewStyle().Foreground(color(t.Border))
+	CursorStyle = FocusedStyle
+	SpinnerStyle = FocusedStyle
+	ProgressStyle = FocusedStyle
+	ProgressEmptyStyle = BlurredStyle
+	StatusBarStyle = lipgloss.NewStyle().Foreground(color(t.StatusFg)).Background(color(t.StatusBg)).Reverse(t.StatusBg == "")
+	StatusBarAccentStyle = lipgloss.NewStyle().Foreground(color(t.SelectedFg)).Background(color(t.SelectedBg)).Bold(true).Reverse(t.SelectedBg == "")
+	MarkdownStyle = t.Markdown
+}
+
+// RenderMarkdown renders markdown in the style of the theme, wrapped at
+// width or at 80 columns if width is 0.
+func RenderMarkdown(content string, width int) (string, error) {
+	if width <= 0 {
+		width = 80
+	}
+	r, err := glamour.NewTermRenderer(glamour.WithStylePath(MarkdownStyle), glamour.WithWordWrap(width))
+	if err != nil {
+		return "", err
+	}
+	return r.Render(content)
+}
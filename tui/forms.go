@@ -0,0 +1,72 @@
+package tui
+
+import (
+	"fmt"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/jonsth131/ctfd-cli/tui/constants"
+)
+
+func newFormInput(placeholder string) textinput.Model {
+	t := textinput.New()
+	t.Cursor.Style = constants.CursorStyle
+	t.CharLimit = 255
+	t.Placeholder = placeholder
+	return t
+}
+
+func passwordInput(t textinput.Model) textinput.Model {
+	t.EchoMode = textinput.EchoPassword
+	t.EchoCharacter = '•'
+	return t
+}
+
+// moveFocus moves the focus of a form for tab, shift+tab, up and down. The
+// index one past the last input is the submit button.
+func moveFocus(inputs []textinput.Model, focusIndex int, key string) int {
+	if key == "up" || key == "shift+tab" {
+		focusIndex--
+	} else {
+		focusIndex++
+	}
+
+	if focusIndex > len(inputs) {
+		focusIndex = 0
+	} else if focusIndex < 0 {
+		focusIndex = len(inputs)
+	}
+	return focusIndex
+}
+
+// focusInputs focuses the input at focusIndex and blurs all others.
+func focusInputs(inputs []textinput.Model, focusIndex int) tea.Cmd {
+	cmds := make([]tea.Cmd, len(inputs))
+	for i := range inputs {
+		if i == focusIndex {
+			cmds[i] = inputs[i].Focus()
+			inputs[i].PromptStyle = constants.FocusedStyle
+			inputs[i].TextStyle = constants.FocusedStyle
+			continue
+		}
+		inputs[i].Blur()
+		inputs[i].PromptStyle = constants.NoStyle
+		inputs[i].TextStyle = constants.NoStyle
+	}
+	return tea.Batch(cmds...)
+}
+
+func updateInputs(inputs []textinput.Model, msg tea.Msg) tea.Cmd {
+	cmds := make([]tea.Cmd, len(inputs))
+	for i := range inputs {
+		inputs[i], cmds[i] = inputs[i].Update(msg)
+	}
+	return tea.Batch(cmds...)
+}
+
+func submitButton(focused bool) string {
+	if focused {
+		return constants.FocusedStyle.Render("[ Submit ]")
+	}
+	return fmt.Sprintf("[ %s ]", constants.BlurredStyle.Render("Submit"))
+}
@@ -0,0 +1,86 @@
+package tui
+
+import "unicode"
+
+// fuzzyMatch reports whether the runes of pattern appear in text in order,
+// ignoring case and spaces in the pattern. The score favours matches at the
+// start of words and runs of consecutive runes, and positions holds the
+// indexes of the matched runes in text.
+func fuzzyMatch(pattern, text string) (score int, positions []int, ok bool) {
+	var p []rune
+	for _, r := range pattern {
+		if !unicode.IsSpace(r) {
+			p = append(p, unicode.ToLower(r))
+		}
+	}
+	if len(p) == 0 {
+		return 0, nil, true
+	}
+
+	t := []rune(text)
+	lower := make([]rune, len(t))
+	for i, r := range t {
+		lower[i] = unicode.ToLower(r)
+	}
+
+	// Try every start of the first rune and keep the best, so that a later
+	// match at the start of a word beats an earlier one inside a word.
+	best := -1
+	for start := range lower {
+		if lower[start] != p[0] {
+			continue
+		}
+		s, pos, matched := matchFrom(p, t, lower, start)
+		if matched && (best < 0 || s > score) {
+			best = start
+			score, positions = s, pos
+		}
+	}
+	return score, positions, best >= 0
+}
+
+func matchFrom(p, t, lower []rune, start int) (int, []int, bool) {
+	positions := make([]int, 0, len(p))
+	score := 0
+	prev := -1
+	j := start
+	for _, r := range p {
+		for j < len(lower) && lower[j] != r {
+			j++
+		}
+		if j == len(lower) {
+			return 0, nil, false
+		}
+
+		score++
+		switch {
+		case j == 0:
+			score += 10
+		case isWordStart(t, j):
+			score += 8
+		}
+		if prev >= 0 {
+			if j == prev+1 {
+				score += 5
+			} else {
+				score -= min(j-prev-1, 5)
+			}
+		}
+
+		positions = append(positions, j)
+		prev = j
+		j++
+	}
+	return score, positions, true
+}
+
+func isWordStart(t []rune, i int) bool {
+	prev := t[i-1]
+	switch {
+	case unicode.IsSpace(prev) || unicode.IsPunct(prev):
+		return true
+	case unicode.IsLower(prev) && unicode.IsUpper(t[i]):
+		return true
+	}
+	return false
+}
@@ -0,0 +1,69 @@
+package tui
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestFuzzyMatch(t *testing.T) {
+	tests := []struct {
+		pattern   string
+		text      string
+		ok        bool
+		positions []int
+	}{
+		{"", "anything", true, nil},
+		{"pwn", "Pwn me", true, []int{0, 1, 2}},
+		{"p m", "Pwn me", true, []int{0, 4}},
+		{"web", "Crypto", false, nil},
+		{"ba", "ab", false, nil},
+		// The match at the start of the word beats the earlier one.
+		{"fl", "baffle flag", true, []int{7, 8}},
+		{"hs", "HeapSpray", true, []int{0, 4}},
+	}
+
+	for _, test := range tests {
+		_, positions, ok := fuzzyMatch(test.pattern, test.text)
+		if ok != test.ok || !slices.Equal(positions, test.positions) {
+			t.Errorf("fuzzyMatch(%q, %q) = %v, %v, want %v, %v", test.pattern, test.text, positions, ok, test.positions, test.ok)
+		}
+	}
+}
+
+func TestFuzzyMatchOrder(t *testing.T) {
+	// Each text should score higher than the next for the pattern: the start
+	// of the text, then the start of a word, then inside a word, and runs of
+	// consecutive runes before gaps.
+	tests := []struct {
+		pattern string
+		texts   []string
+	}{
+		{"rev", []string{"Reverse", "Easy rev", "prevent"}},
+		{"sql", []string{"SQL injection", "My SQL", "mysql"}},
+		{"ab", []string{"abc", "axb", "axxxxxxb"}},
+	}
+
+	for _, test := range tests {
+		prev := 0
+		for i, text := range test.texts {
+			score, _, ok := fuzzyMatch(test.pattern, text)
+			if !ok {
+				t.Fatalf("fuzzyMatch(%q, %q) didn't match", test.pattern, text)
+			}
+			if i > 0 && score >= prev {
+				t.Errorf("fuzzyMatch(%q, %q) = %d, want less than %d for %q", test.pattern, text, score, prev, test.texts[i-1])
+			}
+			prev = score
+		}
+	}
+}
+
+func TestMatchFromWordStart(t *testing.T) {
+	p := []rune("b")
+	text := []rune("ab b")
+	inside, _, _ := matchFrom(p, text, text, 1)
+	start, _, _ := matchFrom(p, text, text, 3)
+	if start <= inside {
+		t.Errorf("expected a match at the start of a word to score higher, got %d and %d", start, inside)
+	}
+}
@@ -0,0 +1,132 @@
+package tui
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/jonsth131/ctfd-cli/tui/constants"
+)
+
+// helpScreen is implemented by screens with their own key context, named as
+// in constants.KeyContexts.
+type helpScreen interface {
+	keyContext() string
+}
+
+// typingScreen is implemented by screens with text inputs, which get keys
+// that would otherwise open the help.
+type typingScreen interface {
+	typing() bool
+}
+
+func isTyping(m tea.Model) bool {
+	s, ok := m.(typingScreen)
+	return ok && s.typing()
+}
+
+// navigationActions move between and within screens.
+var navigationActions = append([]string{
+	constants.ActionHistoryBack,
+	constants.ActionHistoryForward,
+	constants.ActionBack,
+}, constants.TableActions...)
+
+// commands are the command-line equivalents of actions, by key context.
+var commands = map[string]map[string]string{
+	"Global": {
+		constants.ActionChallenges: "ctfd-cli challenges",
+	},
+	"Challenge list": {
+		constants.ActionEnter:  "ctfd-cli challenge <id>",
+		constants.ActionReload: "ctfd-cli challenges",
+	},
+	"Challenge": {
+		constants.ActionReload: "ctfd-cli challenge <id>",
+		constants.ActionNotes:  "ctfd-cli notes <id>",
+	},
+}
+
+type helpGroup struct {
+	name    string
+	context string
+	actions []string
+}
+
+// helpGroups splits the global actions and the actions of a key context into
+// global, navigation and screen-specific groups.
+func helpGroups(context string) []helpGroup {
+	global := helpGroup{name: "Global", context: "Global"}
+	navigation := helpGroup{name: "Navigation"}
+	screen := helpGroup{name: context, context: context}
+
+	for _, c := range constants.KeyContexts {
+		if c.Name != "Global" && c.Name != context {
+			continue
+		}
+		for _, action := range c.Actions {
+			switch {
+			case slices.Contains(navigationActions, action):
+				navigation.actions = append(navigation.actions, action)
+			case c.Name == "Global":
+				global.actions = append(global.actions, action)
+			default:
+				screen.actions = append(screen.actions, action)
+			}
+		}
+	}
+
+	groups := []helpGroup{global, navigation}
+	if len(screen.actions) > 0 {
+		groups = append(groups, screen)
+	}
+	return groups
+}
+
+// fullHelp is the FullHelp of the keymap of a screen.
+func fullHelp(context string) [][]key.Binding {
+	var groups [][]key.Binding
+	for _, g := range helpGroups(context) {
+		bindings := make([]key.Binding, len(g.actions))
+		for i, action := range g.actions {
+			bindings[i] = constants.Binding(action)
+		}
+		groups = append(groups, bindings)
+	}
+	return groups
+}
+
+// helpView lists every key binding of the screen, marking the ones set in
+// the config, with the command-line equivalents of the actions.
+func helpView(screen tea.Model) string {
+	context := ""
+	if s, ok := screen.(helpScreen); ok {
+		context = s.keyContext()
+	}
+
+	var b strings.Builder
+	b.WriteString(constants.FocusedStyle.Render("Key bindings"))
+	for _, g := range helpGroups(context) {
+		fmt.Fprintf(&b, "\n\n%s", lipgloss.NewStyle().Bold(true).Render(g.name))
+		for _, action := range g.actions {
+			keys := strings.Join(constants.KeyNames(action), "/")
+			if keys == "" {
+				keys = "unbound"
+			}
+			if constants.IsCustom(action) {
+				keys += "*"
+			}
+			line := fmt.Sprintf("\n  %-18s %-18s", keys, constants.ActionHelp(action))
+			if command, ok := commands[g.context][action]; ok {
+				line += constants.HelpStyle(command)
+			}
+			b.WriteString(strings.TrimRight(line, " "))
+		}
+	}
+	b.WriteString("\n\n" + constants.HelpStyle("* set in the config • "+constants.Keymap.Help.Help().Key+"/esc close"))
+
+	return constants.BaseStyle.Padding(0, 1).Render(b.String())
+}
@@ -0,0 +1,15 @@
+package tui
+
+import "github.com/jonsth131/ctfd-cli/tui/constants"
+
+// loadKeymaps applies the key bindings from the config to every keymap.
+func loadKeymaps() error {
+	if err := constants.LoadKeymap(constants.Config.Keys); err != nil {
+		return err
+	}
+
+	ChallengesKeymap = newChallengesKeymap()
+	ChallengeKeymap = newChallengeKeymap()
+	ScoreboardKeymap = newScoreboardKeymap()
+	return nil
+}
@@ -4,88 +4,199 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"os"
 	"strings"
 
 	"github.com/charmbracelet/bubbles/spinner"
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/jonsth131/ctfd-cli/api"
 	"github.com/jonsth131/ctfd-cli/tui/constants"
 )
 
-var (
-	focusedButton = constants.FocusedStyle.Render("[ Submit ]")
-	blurredButton = fmt.Sprintf("[ %s ]", constants.BlurredStyle.Render("Submit"))
+type (
+	loginMsg struct {
+		username string
+		password string
+		// teamRequired is set when the user has to create or join a team
+		// before playing.
+		teamRequired bool
+	}
 )
 
-type (
-	loginMsg struct{}
+type loginMode int
+
+const (
+	passwordLogin loginMode = iota
+	sessionLogin
+	oauthLogin
+	loginModes
 )
 
 type loginModel struct {
+	mode       loginMode
 	focusIndex int
 	inputs     []textinput.Model
 	spinner    spinner.Model
+	reqs       *requests
 	loading    bool
 	err        error
 	width      int
 	height     int
+	// next is the screen to return to after logging in, nil for the
+	// challenge list.
+	next    tea.Model
+	nextCmd tea.Cmd
+	// authURL belongs to a running OAuth login.
+	authURL string
 }
 
 func InitLogin() (tea.Model, tea.Cmd) {
+	m := newLoginModel()
+	return m, nil
+}
+
+// InitLoginReturning shows the login screen after the session expired and
+// returns to next, running cmd, once logged in again.
+func InitLoginReturning(next tea.Model, cmd tea.Cmd, width, height int) (tea.Model, tea.Cmd) {
+	m := newLoginModel()
+	m.next = next
+	m.nextCmd = cmd
+	m.width = width
+	m.height = height
+	m.err = errMsg{api.ErrSessionExpired}
+	return m, tea.Batch(textinput.Blink, m.spinner.Tick)
+}
+
+func newLoginModel() loginModel {
 	m := loginModel{
-		inputs:  make([]textinput.Model, 2),
+		inputs:  newLoginInputs(passwordLogin),
+		reqs:    newRequests(),
 		loading: false,
 	}
 
+	m.spinner = spinner.New()
+	m.spinner.Style = constants.SpinnerStyle
+	m.spinner.Spinner = spinner.Dot
+
+	return m
+}
+
+func newLoginInputs(mode loginMode) []textinput.Model {
+	inputs := make([]textinput.Model, 2)
+	if mode == oauthLogin {
+		inputs = inputs[:1]
+	}
+
 	var t textinput.Model
-	for i := range m.inputs {
+	for i := range inputs {
 		t = textinput.New()
 		t.Cursor.Style = constants.CursorStyle
 		t.CharLimit = 255
 
-		switch i {
-		case 0:
+		switch {
+		case mode == passwordLogin && i == 0:
 			t.Placeholder = "Username"
-			t.Focus()
-			t.PromptStyle = constants.FocusedStyle
-			t.TextStyle = constants.FocusedStyle
-		case 1:
+			t.SetValue(constants.Profile.User)
+		case mode == passwordLogin && i == 1:
 			t.Placeholder = "Password"
+			t.SetValue(constants.Profile.Password)
+			t.EchoMode = textinput.EchoPassword
+			t.EchoCharacter = '•'
+		case mode == sessionLogin && i == 0:
+			t.Placeholder = "Cookie header or path to cookies.txt"
+			t.CharLimit = 8192
 			t.EchoMode = textinput.EchoPassword
 			t.EchoCharacter = '•'
+		case mode == sessionLogin && i == 1:
+			t.Placeholder = "Browser User-Agent (optional)"
+			t.SetValue(constants.Profile.Network.UserAgent)
+			t.CharLimit = 1024
+		case mode == oauthLogin:
+			t.Placeholder = "URL the browser was redirected to"
+			t.CharLimit = 4096
+		}
+
+		if i == 0 {
+			t.Focus()
+			t.PromptStyle = constants.FocusedStyle
+			t.TextStyle = constants.FocusedStyle
 		}
 
-		m.inputs[i] = t
+		inputs[i] = t
 	}
 
-	m.spinner = spinner.New()
-	m.spinner.Style = constants.SpinnerStyle
-	m.spinner.Spinner = spinner.Dot
+	return inputs
+}
 
-	return m, nil
+// importSessionCmd loads browser cookies, given either as a Cookie header or
+// as the path of a cookies.txt file, and checks that they are logged in.
+func importSessionCmd(r *requests, input, userAgent string) tea.Cmd {
+	return r.cmd(func(ctx context.Context) tea.Msg {
+		if data, err := os.ReadFile(strings.TrimSpace(input)); err == nil {
+			input = string(data)
+		}
+
+		cookies, err := api.ParseCookies(input)
+		if err != nil {
+			return createErrMsg(fmt.Errorf("Failed to import session: %w", err))
+		}
+		if err := constants.C.ImportSession(cookies, strings.TrimSpace(userAgent)); err != nil {
+			return createErrMsg(fmt.Errorf("Failed to import session: %w", err))
+		}
+
+		ctx, cancel := context.WithTimeout(ctx, constants.Timeout)
+		defer cancel()
+		log.Default().Print("Verifying imported session...")
+		user, err := constants.C.GetMe(ctx)
+		if err != nil {
+			return createErrMsg(fmt.Errorf("Imported session doesn't work: %w", err))
+		}
+		log.Default().Printf("Imported session for %s", user.Name)
+		return loginMsg{teamRequired: teamRequired(ctx)}
+	})
 }
 
-func loginCmd(username, password string) tea.Cmd {
-	return func() tea.Msg {
-		ctx, cancel := context.WithTimeout(context.Background(), constants.Timeout)
+func loginCmd(r *requests, username, password string) tea.Cmd {
+	return r.cmd(func(ctx context.Context) tea.Msg {
+		ctx, cancel := context.WithTimeout(ctx, constants.Timeout)
 		defer cancel()
 		log.Default().Print("Logging in...")
 		err := constants.C.Login(ctx, username, password)
 		if err != nil {
-			return createErrMsg(fmt.Errorf("Failed to login: %v", err))
+			return createErrMsg(fmt.Errorf("Failed to login: %w", err))
 		}
 		log.Default().Print("Logged in successfully")
-		return loginMsg{}
+		switchToToken(ctx)
+		return loginMsg{username, password, teamRequired(ctx)}
+	})
+}
+
+// teamRequired checks whether a team has to be created or joined. Failures
+// are only logged, the challenge list reports them better.
+func teamRequired(ctx context.Context) bool {
+	required, err := constants.C.TeamRequired(ctx)
+	if err != nil {
+		log.Default().Printf("Failed to check team: %v", err)
 	}
+	return required
 }
 
 func (m loginModel) Init() tea.Cmd {
 	return tea.Batch(textinput.Blink, m.spinner.Tick)
 }
 
+func (m loginModel) requests() *requests { return m.reqs }
+
+func (m loginModel) typing() bool { return m.focusIndex < len(m.inputs) }
+
 func (m loginModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	msg, cmd := m.reqs.accept(msg)
+	if msg == nil {
+		return m, cmd
+	}
+
 	var cmds []tea.Cmd
-	inputCmds := make([]tea.Cmd, len(m.inputs))
 	switch msg := msg.(type) {
 	case tea.WindowSizeMsg:
 		m.width = msg.Width
@@ -96,8 +207,20 @@ func (m loginModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.err = msg
 		m.loading = false
 		return m, tea.Batch(cmds...)
+	case oauthStartedMsg:
+		if m.mode != oauthLogin {
+			return m, nil
+		}
+		m.authURL = msg.authURL
+		return m, openURLCmd(msg.authURL)
 	case loginMsg:
-		return InitChallenges(m.width, m.height)
+		if msg.username != "" {
+			setCredentials(msg.username, msg.password)
+		}
+		if msg.teamRequired {
+			return InitTeam(m.next, m.nextCmd, m.width, m.height)
+		}
+		return continueTo(m.next, m.nextCmd, m.width, m.height)
 	case spinner.TickMsg:
 		var cmd tea.Cmd
 		m.spinner, cmd = m.spinner.Update(msg)
@@ -107,57 +230,49 @@ func (m loginModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		case "ctrl+c", "esc":
 			return m, tea.Quit
 
+		case "ctrl+t":
+			m.authURL = ""
+			m.reqs.cancel()
+			m.loading = false
+			m.mode = (m.mode + 1) % loginModes
+			m.inputs = newLoginInputs(m.mode)
+			m.focusIndex = 0
+			m.err = nil
+			if m.mode == oauthLogin {
+				return m, tea.Batch(textinput.Blink, startOAuthCmd(m.reqs))
+			}
+			return m, textinput.Blink
+
+		case "ctrl+r":
+			m.reqs.cancel()
+			return InitRegister(m.next, m.nextCmd, m.width, m.height)
+
 		case "tab", "shift+tab", "enter", "up", "down":
 			s := msg.String()
 
 			if s == "enter" && m.focusIndex == len(m.inputs) {
 				m.loading = true
 				m.err = nil
-				cmds = append(cmds, loginCmd(m.inputs[0].Value(), m.inputs[1].Value()))
-			}
-
-			if s == "up" || s == "shift+tab" {
-				m.focusIndex--
-			} else {
-				m.focusIndex++
-			}
-
-			if m.focusIndex > len(m.inputs) {
-				m.focusIndex = 0
-			} else if m.focusIndex < 0 {
-				m.focusIndex = len(m.inputs)
-			}
-
-			for i := 0; i <= len(m.inputs)-1; i++ {
-				if i == m.focusIndex {
-					inputCmds[i] = m.inputs[i].Focus()
-					m.inputs[i].PromptStyle = constants.FocusedStyle
-					m.inputs[i].TextStyle = constants.FocusedStyle
-					continue
+				switch m.mode {
+				case sessionLogin:
+					cmds = append(cmds, importSessionCmd(m.reqs, m.inputs[0].Value(), m.inputs[1].Value()))
+				case oauthLogin:
+					cmds = append(cmds, completeOAuthCmd(m.reqs, m.inputs[0].Value()))
+				default:
+					cmds = append(cmds, loginCmd(m.reqs, m.inputs[0].Value(), m.inputs[1].Value()))
 				}
-				m.inputs[i].Blur()
-				m.inputs[i].PromptStyle = constants.NoStyle
-				m.inputs[i].TextStyle = constants.NoStyle
 			}
+
+			m.focusIndex = moveFocus(m.inputs, m.focusIndex, s)
+			cmds = append(cmds, focusInputs(m.inputs, m.focusIndex))
 		}
 	}
 
-	cmds = append(cmds, m.updateInputs(msg))
-	cmds = append(cmds, inputCmds...)
+	cmds = append(cmds, updateInputs(m.inputs, msg))
 
 	return m, tea.Batch(cmds...)
 }
 
-func (m *loginModel) updateInputs(msg tea.Msg) tea.Cmd {
-	cmds := make([]tea.Cmd, len(m.inputs))
-
-	for i := range m.inputs {
-		m.inputs[i], cmds[i] = m.inputs[i].Update(msg)
-	}
-
-	return tea.Batch(cmds...)
-}
-
 func (m loginModel) View() string {
 	if len(m.inputs) == 0 {
 		return "initializing..."
@@ -169,6 +284,19 @@ func (m loginModel) View() string {
 
 	var b strings.Builder
 
+	switch m.mode {
+	case sessionLogin:
+		b.WriteString("Import a browser session\n\n")
+	case oauthLogin:
+		b.WriteString("Login with OAuth\n\n")
+		if m.authURL == "" {
+			fmt.Fprintf(&b, "%s%s\n\n", m.spinner.View(), "Starting OAuth login...")
+		} else {
+			fmt.Fprintf(&b, "Login in the browser that was opened, or open:\n%s\n\n", m.authURL)
+			b.WriteString(constants.HelpStyle("Then paste the URL of the CTF page the browser ended up on.") + "\n\n")
+		}
+	}
+
 	for i := range m.inputs {
 		b.WriteString(m.inputs[i].View())
 		if i < len(m.inputs)-1 {
@@ -176,14 +304,31 @@ func (m loginModel) View() string {
 		}
 	}
 
-	button := &blurredButton
-	if m.focusIndex == len(m.inputs) {
-		button = &focusedButton
-	}
+	fmt.Fprintf(&b, "\n\n%s\n\n%s", submitButton(m.focusIndex == len(m.inputs)), renderError(m.err))
 
-	errStr := renderError(m.err)
+	switch m.mode {
+	case sessionLogin:
+		fmt.Fprintf(&b, "\n\n%s", constants.HelpStyle("ctrl+t login with OAuth • ctrl+r register"))
+	case oauthLogin:
+		fmt.Fprintf(&b, "\n\n%s", constants.HelpStyle("ctrl+t login with password • ctrl+r register"))
+	default:
+		fmt.Fprintf(&b, "\n\n%s", constants.HelpStyle("ctrl+t import browser session • ctrl+r register"))
+	}
 
-	fmt.Fprintf(&b, "\n\n%s\n\n%s", *button, errStr)
+	if constants.Profile.Network.Insecure {
+		fmt.Fprintf(&b, "\n\n%s", constants.ErrStyle("WARNING: TLS certificate verification is disabled"))
+	}
 
 	return b.String()
 }
+
+// continueTo returns to next after logging in, registering or joining a
+// team, or opens the challenge list when there is no screen to return to.
+func continueTo(next tea.Model, cmd tea.Cmd, width, height int) (tea.Model, tea.Cmd) {
+	if next == nil {
+		m, initCmd := InitChallenges(width, height)
+		return m, tea.Batch(initCmd, refreshStatusCmd())
+	}
+	resize := func() tea.Msg { return tea.WindowSizeMsg{Width: width, Height: height} }
+	return next, tea.Batch(resize, cmd, refreshStatusCmd())
+}
@@ -0,0 +1,88 @@
+package tui
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/bubbles/table"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/jonsth131/ctfd-cli/tui/constants"
+)
+
+// selectedMarker stands in for the style of the selected row when locating
+// rows in the view of a table.
+const selectedMarker = "\uE000"
+
+// tableRowAt returns the index of the row shown at line y of the view of t.
+// The table doesn't tell which rows it has scrolled to, so they are counted
+// from the line of the selected row.
+func tableRowAt(t table.Model, y int) (int, bool) {
+	y -= lipgloss.Height(t.View()) - t.Height()
+	if y < 0 || y >= t.Height() {
+		return 0, false
+	}
+
+	s := constants.TableStyle
+	s.Selected = lipgloss.NewStyle().SetString(selectedMarker)
+	t.SetStyles(s)
+	header := lipgloss.Height(t.View()) - t.Height()
+	for i, line := range strings.Split(t.View(), "\n") {
+		if strings.Contains(line, selectedMarker) {
+			row := t.Cursor() + y - (i - header)
+			return row, row >= 0 && row < len(t.Rows())
+		}
+	}
+	return 0, false
+}
+
+// clickTable moves the cursor of t on wheel events and to rows clicked with
+// the left button. top and left are where the table is drawn on the screen.
+// It reports whether the selected row was clicked again.
+func clickTable(t *table.Model, msg tea.MouseMsg, top, left int) bool {
+	switch msg.Button {
+	case tea.MouseButtonWheelUp:
+		t.MoveUp(1)
+	case tea.MouseButtonWheelDown:
+		t.MoveDown(1)
+	case tea.MouseButtonLeft:
+		if msg.Action != tea.MouseActionPress || msg.X < left || msg.X >= left+lipgloss.Width(t.View()) {
+			return false
+		}
+		row, ok := tableRowAt(*t, msg.Y-top)
+		if !ok {
+			return false
+		}
+		if row == t.Cursor() {
+			return true
+		}
+		t.SetCursor(row)
+	}
+	return false
+}
+
+// buttonsView renders actions as buttons on one line, dimming the disabled.
+func buttonsView(actions []string, enabled func(action string) bool) string {
+	buttons := make([]string, len(actions))
+	for i, action := range actions {
+		label := capitalize(constants.ActionHelp(action))
+		if enabled(action) {
+			buttons[i] = constants.FocusedStyle.Render("[ " + label + " ]")
+		} else {
+			buttons[i] = "[ " + constants.BlurredStyle.Render(label) + " ]"
+		}
+	}
+	return strings.Join(buttons, " ")
+}
+
+// buttonAt returns the action of the button at column x of buttonsView.
+func buttonAt(actions []string, x int) (string, bool) {
+	pos := 0
+	for _, action := range actions {
+		w := lipgloss.Width("[ " + capitalize(constants.ActionHelp(action)) + " ]")
+		if x >= pos && x < pos+w {
+			return action, true
+		}
+		pos += w + 1
+	}
+	return "", false
+}
@@ -0,0 +1,79 @@
+package tui
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/charmbracelet/bubbles/table"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/jonsth131/ctfd-cli/tui/constants"
+)
+
+func TestButtonAt(t *testing.T) {
+	actions := []string{constants.ActionSubmit, constants.ActionHints}
+	// "[ Submit flag ] [ Hints ]"
+	submit := lipgloss.Width("[ Submit flag ]")
+
+	tests := []struct {
+		x        int
+		expected string
+		ok       bool
+	}{
+		{-1, "", false},
+		{0, constants.ActionSubmit, true},
+		{submit - 1, constants.ActionSubmit, true},
+		{submit, "", false},
+		{submit + 1, constants.ActionHints, true},
+		{submit + 1 + lipgloss.Width("[ Hints ]"), "", false},
+	}
+
+	for _, test := range tests {
+		action, ok := buttonAt(actions, test.x)
+		if action != test.expected || ok != test.ok {
+			t.Errorf("buttonAt(%d) = %q, %v, want %q, %v", test.x, action, ok, test.expected, test.ok)
+		}
+	}
+
+	if w := lipgloss.Width(buttonsView(actions, func(string) bool { return true })); w != submit+1+lipgloss.Width("[ Hints ]") {
+		t.Errorf("expected buttonAt to cover the %d columns of buttonsView, got %d", w, submit+1+lipgloss.Width("[ Hints ]"))
+	}
+}
+
+func TestTableRowAt(t *testing.T) {
+	rows := make([]table.Row, 10)
+	for i := range rows {
+		rows[i] = table.Row{fmt.Sprint(i)}
+	}
+	tbl := table.New(
+		table.WithColumns([]table.Column{{Title: "Id", Width: 4}}),
+		table.WithRows(rows),
+		table.WithHeight(4),
+		table.WithStyles(constants.TableStyle),
+	)
+	header := lipgloss.Height(tbl.View()) - tbl.Height()
+
+	tests := []struct {
+		name     string
+		cursor   int
+		y        int
+		expected int
+		ok       bool
+	}{
+		{"Header", 0, header - 1, 0, false},
+		{"First row", 0, header, 0, true},
+		{"Second row", 0, header + 1, 1, true},
+		{"Below the table", 0, header + tbl.Height(), 0, false},
+		{"Scrolled to the end", 9, header + tbl.Height() - 1, 9, true},
+		{"Above the selected row", 9, header + tbl.Height() - 2, 8, true},
+	}
+
+	for _, test := range tests {
+		// Moving scrolls the table like the keys and the wheel do.
+		tbl.GotoTop()
+		tbl.MoveDown(test.cursor)
+		row, ok := tableRowAt(tbl, test.y)
+		if row != test.expected || ok != test.ok {
+			t.Errorf("%s: tableRowAt(%d) = %d, %v, want %d, %v", test.name, test.y, row, ok, test.expected, test.ok)
+		}
+	}
+}
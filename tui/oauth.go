@@ -0,0 +1,57 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/jonsth131/ctfd-cli/api"
+	"github.com/jonsth131/ctfd-cli/tui/constants"
+)
+
+type oauthStartedMsg struct {
+	authURL string
+}
+
+// startOAuthCmd starts an OAuth login. The provider sends the browser back
+// to the CTF's /redirect page, the URL it ends up on has to be pasted.
+func startOAuthCmd(r *requests) tea.Cmd {
+	return r.cmd(func(ctx context.Context) tea.Msg {
+		ctx, cancel := context.WithTimeout(ctx, constants.Timeout)
+		defer cancel()
+		log.Default().Print("Starting OAuth login...")
+		authURL, err := constants.C.StartOAuth(ctx)
+		if err != nil {
+			return createErrMsg(fmt.Errorf("Failed to start OAuth login: %w", err))
+		}
+		return oauthStartedMsg{authURL}
+	})
+}
+
+// completeOAuthCmd finishes the OAuth login and saves the session to the
+// profile, as there is no password to login again with.
+func completeOAuthCmd(r *requests, redirectURL string) tea.Cmd {
+	return r.cmd(func(ctx context.Context) tea.Msg {
+		ctx, cancel := context.WithTimeout(ctx, constants.Timeout)
+		defer cancel()
+		if err := constants.C.CompleteOAuth(ctx, strings.TrimSpace(redirectURL)); err != nil {
+			return createErrMsg(err)
+		}
+
+		user, err := constants.C.GetMe(ctx)
+		if err != nil {
+			return createErrMsg(fmt.Errorf("OAuth login didn't create a session: %w", err))
+		}
+		log.Default().Printf("Logged in as %s with OAuth", user.Name)
+
+		profile := constants.Profile
+		profile.Session = api.CookieHeader(constants.C.SessionCookies())
+		if err := saveProfile(profile); err != nil {
+			log.Default().Printf("Failed to save session: %v", err)
+		}
+
+		return loginMsg{teamRequired: teamRequired(ctx)}
+	})
+}
@@ -0,0 +1,23 @@
+package tui
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// overlay draws fg centered over bg, a third of the way down, replacing the
+// lines of bg it covers.
+func overlay(bg, fg string, width int) string {
+	bgLines := strings.Split(bg, "\n")
+	fgLines := strings.Split(fg, "\n")
+
+	top := max(0, (len(bgLines)-len(fgLines))/3)
+	for len(bgLines) < top+len(fgLines) {
+		bgLines = append(bgLines, "")
+	}
+	for i, line := range fgLines {
+		bgLines[top+i] = lipgloss.PlaceHorizontal(width, lipgloss.Center, line)
+	}
+	return strings.Join(bgLines, "\n")
+}
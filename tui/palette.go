@@ -0,0 +1,226 @@
+package tui
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/cursor"
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/jonsth131/ctfd-cli/tui/constants"
+)
+
+const (
+	paletteWidth   = 64
+	paletteResults = 10
+)
+
+type (
+	// actionMsg runs an action, as if its key was pressed.
+	actionMsg string
+	// openChallengeMsg opens a challenge in the challenges tab.
+	openChallengeMsg struct {
+		id int
+	}
+	// selectTeamMsg selects a team in the scoreboard tab.
+	selectTeamMsg struct {
+		name string
+	}
+)
+
+// actionScreen is implemented by screens whose actions can be run from the
+// command palette.
+type actionScreen interface {
+	actions() []string
+}
+
+// keyAction returns the first of actions bound to the key.
+func keyAction(msg tea.KeyMsg, actions ...string) (string, bool) {
+	for _, action := range actions {
+		if key.Matches(msg, constants.Binding(action)) {
+			return action, true
+		}
+	}
+	return "", false
+}
+
+// globalActions are handled by the root model on every screen.
+var globalActions = []string{
+	constants.ActionChallenges,
+	constants.ActionScoreboard,
+	constants.ActionHistoryBack,
+	constants.ActionHistoryForward,
+	constants.ActionShowHelp,
+	constants.ActionQuit,
+}
+
+type paletteEntry struct {
+	kind  string
+	title string
+	// hint is shown after the title and matched along with it.
+	hint string
+	// msg is sent when the entry is chosen.
+	msg tea.Msg
+}
+
+type paletteMatch struct {
+	entry     paletteEntry
+	score     int
+	positions []int
+}
+
+// paletteModel fuzzy finds challenges, teams and actions.
+type paletteModel struct {
+	input   textinput.Model
+	entries []paletteEntry
+	matches []paletteMatch
+	cursor  int
+}
+
+func newPalette(screen tea.Model) *paletteModel {
+	input := textinput.New()
+	input.Prompt = "> "
+	input.Placeholder = "Challenge, team or action"
+	input.Cursor.Style = constants.CursorStyle
+	input.Cursor.SetMode(cursor.CursorStatic)
+	input.Width = paletteWidth - 6
+	input.Focus()
+
+	p := &paletteModel{
+		input:   input,
+		entries: paletteEntries(screen),
+	}
+	p.filter()
+	return p
+}
+
+func paletteEntries(screen tea.Model) []paletteEntry {
+	var entries []paletteEntry
+
+	for _, c := range shared.getChallenges() {
+		entries = append(entries, paletteEntry{"Challenge", c.Name, c.Category, openChallengeMsg{int(c.Id)}})
+	}
+
+	for _, e := range shared.getScoreboard() {
+		entries = append(entries, paletteEntry{"Team", e.Name, fmt.Sprintf("#%d", e.Position), selectTeamMsg{e.Name}})
+	}
+
+	actions := slices.Clone(globalActions)
+	if s, ok := screen.(actionScreen); ok {
+		for _, action := range s.actions() {
+			if action != constants.ActionEnter && !slices.Contains(globalActions, action) {
+				actions = append(actions, action)
+			}
+		}
+	}
+	for _, action := range actions {
+		entries = append(entries, paletteEntry{
+			kind:  "Action",
+			title: capitalize(constants.ActionHelp(action)),
+			hint:  constants.Binding(action).Help().Key,
+			msg:   actionMsg(action),
+		})
+	}
+
+	return entries
+}
+
+func (p *paletteModel) filter() {
+	p.matches = p.matches[:0]
+	for _, e := range p.entries {
+		score, positions, ok := fuzzyMatch(p.input.Value(), e.title+" "+e.hint)
+		if ok {
+			p.matches = append(p.matches, paletteMatch{e, score, positions})
+		}
+	}
+	sort.SliceStable(p.matches, func(i, j int) bool {
+		return p.matches[i].score > p.matches[j].score
+	})
+	p.cursor = 0
+}
+
+// update handles a key press and returns the message of the chosen entry, if
+// any, and whether the palette should be closed.
+func (p *paletteModel) update(msg tea.KeyMsg) (tea.Msg, bool) {
+	switch msg.Type {
+	case tea.KeyEsc:
+		return nil, true
+	case tea.KeyEnter:
+		if len(p.matches) == 0 {
+			return nil, true
+		}
+		return p.matches[p.cursor].entry.msg, true
+	case tea.KeyUp, tea.KeyShiftTab:
+		if p.cursor > 0 {
+			p.cursor--
+		}
+		return nil, false
+	case tea.KeyDown, tea.KeyTab:
+		if p.cursor < len(p.matches)-1 {
+			p.cursor++
+		}
+		return nil, false
+	}
+	if key.Matches(msg, constants.Keymap.Palette) {
+		return nil, true
+	}
+
+	value := p.input.Value()
+	p.input, _ = p.input.Update(msg)
+	if p.input.Value() != value {
+		p.filter()
+	}
+	return nil, false
+}
+
+func (p *paletteModel) View() string {
+	var b strings.Builder
+	b.WriteString(p.input.View())
+	b.WriteString("\n")
+
+	// Scroll the results so that the cursor stays visible.
+	first := max(0, p.cursor-paletteResults+1)
+	last := min(len(p.matches), first+paletteResults)
+	for i := first; i < last; i++ {
+		b.WriteString("\n")
+		b.WriteString(p.matchView(p.matches[i], i == p.cursor))
+	}
+	if len(p.matches) == 0 {
+		b.WriteString("\n" + constants.HelpStyle("No matches"))
+	}
+
+	return constants.BaseStyle.Padding(0, 1).Width(paletteWidth).Render(b.String())
+}
+
+func (p *paletteModel) matchView(m paletteMatch, selected bool) string {
+	title := []rune(m.entry.title)
+	highlighted := map[int]bool{}
+	for _, i := range m.positions {
+		highlighted[i] = true
+	}
+
+	var t strings.Builder
+	for i, r := range title {
+		if highlighted[i] && !selected {
+			t.WriteString(constants.FocusedStyle.Render(string(r)))
+		} else {
+			t.WriteRune(r)
+		}
+	}
+
+	line := fmt.Sprintf("%-9s %s", m.entry.kind, t.String())
+	hint := m.entry.hint
+	width := paletteWidth - 4
+	if pad := width - lipgloss.Width(line) - lipgloss.Width(hint); pad > 0 {
+		line += strings.Repeat(" ", pad) + hint
+	}
+
+	if selected {
+		return constants.SelectedRowStyle.Width(width).MaxWidth(width).Render(line)
+	}
+	return lipgloss.NewStyle().MaxWidth(width).Render(line)
+}
@@ -0,0 +1,25 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/jonsth131/ctfd-cli/tui/constants"
+)
+
+// progressWidth is the width of the bar in progress bars, without the count.
+const progressWidth = 30
+
+// renderProgress draws a bar of width cells filled done/total, followed by
+// the count.
+func renderProgress(done, total, width int) string {
+	filled := 0
+	if total > 0 {
+		filled = min(done, total) * width / total
+	}
+
+	return fmt.Sprintf("%s%s %d/%d",
+		constants.ProgressStyle.Render(strings.Repeat("█", filled)),
+		constants.ProgressEmptyStyle.Render(strings.Repeat("░", width-filled)),
+		done, total)
+}
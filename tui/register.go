@@ -0,0 +1,244 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/spinner"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/jonsth131/ctfd-cli/api"
+	"github.com/jonsth131/ctfd-cli/tui/constants"
+)
+
+type registrationFormMsg struct {
+	form *api.RegistrationForm
+}
+
+const (
+	registerName = iota
+	registerEmail
+	registerPassword
+)
+
+type registerModel struct {
+	form       *api.RegistrationForm
+	focusIndex int
+	inputs     []textinput.Model
+	// codeIndex is the index of the registration code input, -1 if the
+	// instance doesn't ask for one. Custom fields follow it.
+	codeIndex int
+	spinner   spinner.Model
+	reqs      *requests
+	loading   bool
+	err       error
+	width     int
+	height    int
+	next      tea.Model
+	nextCmd   tea.Cmd
+}
+
+// InitRegister shows the registration form. next and cmd are handed back to
+// the login flow once the account is created.
+func InitRegister(next tea.Model, cmd tea.Cmd, width, height int) (tea.Model, tea.Cmd) {
+	m := registerModel{
+		loading: true,
+		reqs:    newRequests(),
+		width:   width,
+		height:  height,
+		next:    next,
+		nextCmd: cmd,
+	}
+
+	m.spinner = spinner.New()
+	m.spinner.Style = constants.SpinnerStyle
+	m.spinner.Spinner = spinner.Dot
+
+	return m, m.Init()
+}
+
+func fetchRegistrationFormCmd(r *requests) tea.Cmd {
+	return r.cmd(func(ctx context.Context) tea.Msg {
+		ctx, cancel := context.WithTimeout(ctx, constants.Timeout)
+		defer cancel()
+		log.Default().Print("Fetching registration form...")
+		form, err := constants.C.GetRegistrationForm(ctx)
+		if err != nil {
+			return createErrMsg(fmt.Errorf("Failed to open registration: %w", err))
+		}
+		return registrationFormMsg{form}
+	})
+}
+
+func registerCmd(reqs *requests, r api.Registration) tea.Cmd {
+	return reqs.cmd(func(ctx context.Context) tea.Msg {
+		ctx, cancel := context.WithTimeout(ctx, constants.Timeout)
+		defer cancel()
+		log.Default().Printf("Registering %s...", r.Name)
+		if err := constants.C.Register(ctx, r); err != nil {
+			return createErrMsg(err)
+		}
+		log.Default().Print("Registered successfully")
+		return loginMsg{r.Name, r.Password, teamRequired(ctx)}
+	})
+}
+
+func newRegisterInputs(form *api.RegistrationForm) ([]textinput.Model, int) {
+	inputs := []textinput.Model{
+		newFormInput("Username"),
+		newFormInput("Email"),
+		passwordInput(newFormInput("Password")),
+	}
+
+	codeIndex := -1
+	if form.CodeRequired {
+		codeIndex = len(inputs)
+		inputs = append(inputs, passwordInput(newFormInput("Registration code")))
+	}
+
+	for _, field := range form.Fields {
+		placeholder := field.Name
+		if field.Type == "checkbox" {
+			placeholder += " (y/n)"
+		}
+		if !field.Required {
+			placeholder += " (optional)"
+		}
+		inputs = append(inputs, newFormInput(placeholder))
+	}
+
+	return inputs, codeIndex
+}
+
+func (m registerModel) registration() api.Registration {
+	r := api.Registration{
+		Name:     m.inputs[registerName].Value(),
+		Email:    m.inputs[registerEmail].Value(),
+		Password: m.inputs[registerPassword].Value(),
+		Fields:   map[int]string{},
+	}
+
+	first := registerPassword + 1
+	if m.codeIndex >= 0 {
+		r.RegistrationCode = m.inputs[m.codeIndex].Value()
+		first = m.codeIndex + 1
+	}
+
+	for i, field := range m.form.Fields {
+		value := strings.TrimSpace(m.inputs[first+i].Value())
+		if field.Type == "checkbox" {
+			if !strings.HasPrefix(strings.ToLower(value), "y") {
+				continue
+			}
+			value = "y"
+		}
+		if value != "" {
+			r.Fields[field.Id] = value
+		}
+	}
+
+	return r
+}
+
+func (m registerModel) Init() tea.Cmd {
+	return tea.Batch(fetchRegistrationFormCmd(m.reqs), m.spinner.Tick)
+}
+
+func (m registerModel) requests() *requests { return m.reqs }
+
+func (m registerModel) typing() bool { return m.focusIndex < len(m.inputs) }
+
+func (m registerModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	msg, cmd := m.reqs.accept(msg)
+	if msg == nil {
+		return m, cmd
+	}
+
+	var cmds []tea.Cmd
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		return m, nil
+	case errMsg:
+		log.Default().Print(msg)
+		m.err = msg
+		m.loading = false
+		return m, nil
+	case registrationFormMsg:
+		m.form = msg.form
+		m.inputs, m.codeIndex = newRegisterInputs(msg.form)
+		m.focusIndex = 0
+		m.loading = false
+		return m, tea.Batch(focusInputs(m.inputs, m.focusIndex), textinput.Blink)
+	case loginMsg:
+		setCredentials(msg.username, msg.password)
+		if msg.teamRequired {
+			return InitTeam(m.next, m.nextCmd, m.width, m.height)
+		}
+		return continueTo(m.next, m.nextCmd, m.width, m.height)
+	case spinner.TickMsg:
+		var cmd tea.Cmd
+		m.spinner, cmd = m.spinner.Update(msg)
+		return m, cmd
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "ctrl+c":
+			return m, tea.Quit
+
+		case "esc":
+			m.reqs.cancel()
+			login := newLoginModel()
+			login.next, login.nextCmd = m.next, m.nextCmd
+			login.width, login.height = m.width, m.height
+			return login, login.Init()
+
+		case "tab", "shift+tab", "enter", "up", "down":
+			if m.form == nil || m.loading {
+				return m, nil
+			}
+
+			s := msg.String()
+			if s == "enter" && m.focusIndex == len(m.inputs) {
+				m.loading = true
+				m.err = nil
+				cmds = append(cmds, registerCmd(m.reqs, m.registration()))
+			}
+
+			m.focusIndex = moveFocus(m.inputs, m.focusIndex, s)
+			cmds = append(cmds, focusInputs(m.inputs, m.focusIndex))
+		}
+	}
+
+	cmds = append(cmds, updateInputs(m.inputs, msg))
+
+	return m, tea.Batch(cmds...)
+}
+
+func (m registerModel) View() string {
+	if m.loading {
+		if m.form == nil {
+			return fmt.Sprintf("\n %s%s", m.spinner.View(), "Loading registration form...")
+		}
+		return fmt.Sprintf("\n %s%s", m.spinner.View(), "Registering...")
+	}
+
+	var b strings.Builder
+	b.WriteString("Register a new account\n\n")
+
+	for i := range m.inputs {
+		b.WriteString(m.inputs[i].View())
+		b.WriteRune('\n')
+	}
+
+	if m.form != nil {
+		fmt.Fprintf(&b, "\n%s\n\n", submitButton(m.focusIndex == len(m.inputs)))
+	}
+	b.WriteString(renderError(m.err))
+
+	fmt.Fprintf(&b, "\n\n%s", constants.HelpStyle("esc back to login"))
+
+	return b.String()
+}
@@ -0,0 +1,224 @@
+package tui
+
+import (
+	"context"
+	"log"
+	"sync/atomic"
+	"time"
+
+	"github.com/charmbracelet/bubbles/spinner"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/jonsth131/ctfd-cli/cache"
+	"github.com/jonsth131/ctfd-cli/tui/constants"
+)
+
+// appCtx is cancelled when the program quits, aborting every request.
+var appCtx = context.Background()
+
+// generations hands out request generations, unique across all screens so a
+// result can never be mistaken for one of another screen.
+var generations atomic.Uint64
+
+// resultMsg is the result of a request made by a screen, tagged with the
+// generation of the screen's requests when it was made.
+type resultMsg struct {
+	gen uint64
+	msg tea.Msg
+}
+
+// progressMsg reports how far an operation made of many requests has come.
+type progressMsg struct {
+	done  int
+	total int
+}
+
+// reloadMsg asks a screen to fetch its data again because requests were
+// cancelled before they completed.
+type reloadMsg struct{}
+
+// requests owns the in-flight requests of a screen. Cancelling them aborts
+// the requests and starts a new generation, so that results arriving late
+// are dropped instead of landing in a screen that didn't ask for them.
+//
+// While requests are running a spinner is shown with the time they have
+// taken so far, and afterwards when the screen's data was last updated.
+type requests struct {
+	ctx         context.Context
+	cancelFunc  context.CancelFunc
+	gen         uint64
+	pending     int
+	interrupted bool
+	spinner     spinner.Model
+	started     time.Time
+	updated     time.Time
+	progress    *progressMsg
+}
+
+// requester is implemented by screens that own requests.
+type requester interface {
+	requests() *requests
+}
+
+func newRequests() *requests {
+	r := &requests{spinner: spinner.New()}
+	r.spinner.Style = constants.SpinnerStyle
+	r.spinner.Spinner = spinner.Dot
+	r.reset()
+	return r
+}
+
+func (r *requests) reset() {
+	r.ctx, r.cancelFunc = context.WithCancel(appCtx)
+	r.gen = generations.Add(1)
+	r.pending = 0
+	r.progress = nil
+}
+
+// start counts a new request, starting the spinner if it is the first.
+func (r *requests) start(cmd tea.Cmd) tea.Cmd {
+	r.pending++
+	if r.pending > 1 {
+		return cmd
+	}
+	r.started = time.Now()
+	return tea.Batch(cmd, r.spinner.Tick)
+}
+
+// cmd runs fn with the context of the screen and tags its result.
+func (r *requests) cmd(fn func(ctx context.Context) tea.Msg) tea.Cmd {
+	ctx := r.ctx
+	return r.start(tagged(r.gen, func() tea.Msg { return fn(ctx) }))
+}
+
+// sessionCmd is cmd, logging in again if the session has expired.
+func (r *requests) sessionCmd(fn func(ctx context.Context) tea.Msg) tea.Cmd {
+	ctx := r.ctx
+	return r.start(tagged(r.gen, withSession(func() tea.Msg { return fn(ctx) })))
+}
+
+// progressCmd is sessionCmd for operations made of many requests. fn calls
+// progress as they complete, which is shown as a progress bar.
+func (r *requests) progressCmd(fn func(ctx context.Context, progress func(done, total int)) tea.Msg) tea.Cmd {
+	gen := r.gen
+	progress := func(done, total int) {
+		if constants.P != nil {
+			constants.P.Send(resultMsg{gen, progressMsg{done, total}})
+		}
+	}
+	return r.sessionCmd(func(ctx context.Context) tea.Msg { return fn(ctx, progress) })
+}
+
+func tagged(gen uint64, cmd tea.Cmd) tea.Cmd {
+	return func() tea.Msg {
+		msg := cmd()
+		if msg == nil {
+			return nil
+		}
+		if expired, ok := msg.(sessionExpiredMsg); ok {
+			// The retry after logging in again belongs to the same screen.
+			expired.retry = tagged(gen, expired.retry)
+			msg = expired
+		}
+		return resultMsg{gen, msg}
+	}
+}
+
+// accept unwraps the results of the current generation. Results of earlier
+// generations or other screens are dropped, as are the ticks of the spinner,
+// which are handled here. Other messages pass through. A nil message means
+// there is nothing left for the screen to handle.
+func (r *requests) accept(msg tea.Msg) (tea.Msg, tea.Cmd) {
+	switch msg := msg.(type) {
+	case spinner.TickMsg:
+		if msg.ID != r.spinner.ID() {
+			return msg, nil
+		}
+		if r.pending == 0 {
+			return nil, nil
+		}
+		var cmd tea.Cmd
+		r.spinner, cmd = r.spinner.Update(msg)
+		return nil, cmd
+	case resultMsg:
+		if msg.gen != r.gen {
+			log.Default().Printf("Dropping stale result: %T", msg.msg)
+			return nil, nil
+		}
+		if progress, ok := msg.msg.(progressMsg); ok {
+			r.progress = &progress
+			return nil, nil
+		}
+		if r.pending > 0 {
+			r.pending--
+		}
+		if r.pending == 0 {
+			r.progress = nil
+		}
+		return msg.msg, nil
+	}
+	return msg, nil
+}
+
+// loaded records that the data of the screen was updated, at the time it
+// was cached if it is stale.
+func (r *requests) loaded(status cache.Status) {
+	if status.Stale {
+		r.updated = status.Since
+	} else {
+		r.updated = time.Now()
+	}
+}
+
+func (r *requests) busy() bool {
+	return r.pending > 0
+}
+
+// view shows the spinner and progress of running requests, or when the data
+// was last updated.
+func (r *requests) view() string {
+	if !r.busy() {
+		if r.updated.IsZero() {
+			return ""
+		}
+		return constants.HelpStyle("Updated " + r.updated.Format("15:04:05"))
+	}
+
+	elapsed := time.Since(r.started).Truncate(time.Second)
+	s := r.spinner.View() + "Loading... " + constants.HelpStyle(elapsed.String())
+	if r.progress != nil {
+		s += "  " + renderProgress(r.progress.done, r.progress.total, progressWidth)
+	}
+	return s
+}
+
+// cancel aborts the in-flight requests.
+func (r *requests) cancel() {
+	if r.pending > 0 {
+		r.interrupted = true
+	}
+	r.cancelFunc()
+	r.reset()
+}
+
+// resume returns a reloadMsg if requests were cancelled before they
+// completed.
+func (r *requests) resume() tea.Cmd {
+	if !r.interrupted {
+		return nil
+	}
+	r.interrupted = false
+	return func() tea.Msg { return reloadMsg{} }
+}
+
+func cancelRequests(m tea.Model) {
+	if s, ok := m.(requester); ok {
+		s.requests().cancel()
+	}
+}
+
+func resumeRequests(m tea.Model) tea.Cmd {
+	if s, ok := m.(requester); ok {
+		return s.requests().resume()
+	}
+	return nil
+}
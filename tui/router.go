@@ -0,0 +1,235 @@
+package tui
+
+import (
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/jonsth131/ctfd-cli/tui/constants"
+)
+
+type tab int
+
+const (
+	challengesTab tab = iota
+	scoreboardTab
+)
+
+type (
+	// navigateMsg opens model on top of the current screen, which is kept
+	// in the history.
+	navigateMsg struct {
+		model tea.Model
+		cmd   tea.Cmd
+	}
+	backMsg      struct{}
+	forwardMsg   struct{}
+	switchTabMsg struct {
+		tab tab
+	}
+)
+
+func navigate(model tea.Model, cmd tea.Cmd) tea.Cmd {
+	return func() tea.Msg { return navigateMsg{model, cmd} }
+}
+
+func back() tea.Msg { return backMsg{} }
+
+func forward() tea.Msg { return forwardMsg{} }
+
+func switchTab(t tab) tea.Cmd {
+	return func() tea.Msg { return switchTabMsg{t} }
+}
+
+// history is the navigation stack of a tab.
+type history struct {
+	current tea.Model
+	back    []tea.Model
+	forward []tea.Model
+}
+
+// rootModel owns every screen. Each tab has its own history, so switching
+// tabs or going back returns to a screen exactly as it was left. Screens
+// that return a different model from Update, like the login screen, replace
+// themselves without adding to the history. The requests of a screen that is
+// left are cancelled and fetched again when it is shown again.
+type rootModel struct {
+	tabs   map[tab]*history
+	active tab
+	init   tea.Cmd
+	// palette is the open command palette, which gets every key press.
+	palette *paletteModel
+	// help is whether the full help is shown over the screen.
+	help   bool
+	width  int
+	height int
+}
+
+// newRootModel starts with the initial screen, running cmd as returned by
+// its constructor.
+func newRootModel(initial tea.Model, cmd tea.Cmd) rootModel {
+	return rootModel{
+		tabs:   map[tab]*history{challengesTab: {current: initial}},
+		active: challengesTab,
+		init:   cmd,
+	}
+}
+
+func initTab(t tab, width, height int) (tea.Model, tea.Cmd) {
+	if t == scoreboardTab {
+		return InitScoreboard(width, height)
+	}
+	return InitChallenges(width, height)
+}
+
+func (r rootModel) history() *history {
+	return r.tabs[r.active]
+}
+
+// show brings a screen that was hidden up to date with the window size and
+// reloads what was cancelled when it was left.
+func (r rootModel) show() tea.Cmd {
+	h := r.history()
+	var cmd tea.Cmd
+	h.current, cmd = h.current.Update(tea.WindowSizeMsg{Width: r.width, Height: r.height})
+	return tea.Batch(cmd, resumeRequests(h.current))
+}
+
+// activate switches to a tab, opening it if it hasn't been yet.
+func (r *rootModel) activate(t tab) tea.Cmd {
+	if t == r.active {
+		return nil
+	}
+	cancelRequests(r.history().current)
+	r.active = t
+	if _, ok := r.tabs[t]; ok {
+		return r.show()
+	}
+	model, cmd := initTab(t, r.width, r.height)
+	r.tabs[t] = &history{current: model}
+	return cmd
+}
+
+func (r rootModel) Init() tea.Cmd {
+	return tea.Batch(r.init, r.history().current.Init())
+}
+
+func (r rootModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	h := r.history()
+
+	switch msg := msg.(type) {
+	case statusTickMsg:
+		// Only redraws the status bar, the screens don't need it.
+		return r, nil
+	case tea.WindowSizeMsg:
+		r.width = msg.Width
+		r.height = msg.Height
+	case navigateMsg:
+		cancelRequests(h.current)
+		h.back = append(h.back, h.current)
+		h.forward = nil
+		h.current = msg.model
+		return r, msg.cmd
+	case backMsg:
+		if len(h.back) == 0 {
+			return r, nil
+		}
+		cancelRequests(h.current)
+		h.forward = append(h.forward, h.current)
+		h.current = h.back[len(h.back)-1]
+		h.back = h.back[:len(h.back)-1]
+		return r, r.show()
+	case forwardMsg:
+		if len(h.forward) == 0 {
+			return r, nil
+		}
+		cancelRequests(h.current)
+		h.back = append(h.back, h.current)
+		h.current = h.forward[len(h.forward)-1]
+		h.forward = h.forward[:len(h.forward)-1]
+		return r, r.show()
+	case switchTabMsg:
+		return r, r.activate(msg.tab)
+	case openChallengeMsg:
+		cmd := r.activate(challengesTab)
+		model, initCmd := InitChallenge(msg.id, r.width, r.height)
+		return r, tea.Batch(cmd, navigate(model, initCmd))
+	case selectTeamMsg:
+		cmd := r.activate(scoreboardTab)
+		h = r.history()
+		var selectCmd tea.Cmd
+		h.current, selectCmd = h.current.Update(msg)
+		return r, tea.Batch(cmd, selectCmd)
+	case actionMsg:
+		switch string(msg) {
+		case constants.ActionQuit:
+			return r, tea.Quit
+		case constants.ActionHistoryBack:
+			return r, back
+		case constants.ActionHistoryForward:
+			return r, forward
+		case constants.ActionChallenges:
+			return r, r.activate(challengesTab)
+		case constants.ActionScoreboard:
+			return r, r.activate(scoreboardTab)
+		case constants.ActionShowHelp:
+			r.help = !r.help
+			return r, nil
+		}
+	case tea.MouseMsg:
+		// The palette and the help cover the screen.
+		if r.palette != nil || r.help {
+			return r, nil
+		}
+	case tea.KeyMsg:
+		if r.palette != nil {
+			chosen, closed := r.palette.update(msg)
+			if closed {
+				r.palette = nil
+			}
+			if chosen != nil {
+				return r, func() tea.Msg { return chosen }
+			}
+			return r, nil
+		}
+		if r.help {
+			switch {
+			case key.Matches(msg, constants.Keymap.Quit):
+				return r, tea.Quit
+			case key.Matches(msg, constants.Keymap.Help, constants.Keymap.Back), msg.Type == tea.KeyEsc:
+				r.help = false
+			}
+			return r, nil
+		}
+
+		// Keys typed into an input belong to the screen.
+		if isTyping(h.current) {
+			break
+		}
+		switch {
+		case key.Matches(msg, constants.Keymap.HistoryBack):
+			return r, back
+		case key.Matches(msg, constants.Keymap.HistoryForward):
+			return r, forward
+		case key.Matches(msg, constants.Keymap.Palette):
+			r.palette = newPalette(h.current)
+			return r, nil
+		case key.Matches(msg, constants.Keymap.Help):
+			r.help = true
+			return r, nil
+		}
+	}
+
+	var cmd tea.Cmd
+	h.current, cmd = h.current.Update(msg)
+	return r, cmd
+}
+
+func (r rootModel) View() string {
+	view := r.history().current.View()
+	switch {
+	case r.palette != nil:
+		return overlay(view, r.palette.View(), r.width)
+	case r.help:
+		return overlay(view, helpView(r.history().current), r.width)
+	}
+	return view
+}
@@ -11,54 +11,68 @@ import (
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/jonsth131/ctfd-cli/api"
+	"github.com/jonsth131/ctfd-cli/cache"
 	"github.com/jonsth131/ctfd-cli/tui/constants"
 )
 
 type scoreboardKeymap struct {
-	Reload key.Binding
-	Quit   key.Binding
+	LineUp     key.Binding
+	LineDown   key.Binding
+	Reload     key.Binding
+	Challenges key.Binding
+	Help       key.Binding
+	Quit       key.Binding
 }
 
 func (k scoreboardKeymap) ShortHelp() []key.Binding {
-	return []key.Binding{k.Reload, k.Quit}
+	return []key.Binding{k.LineUp, k.LineDown, k.Reload, k.Challenges, k.Help, k.Quit}
 }
 
 func (k scoreboardKeymap) FullHelp() [][]key.Binding {
-	return [][]key.Binding{
-		k.ShortHelp(),
-	}
+	return fullHelp("Scoreboard")
 }
 
-var ScoreboardKeymap = scoreboardKeymap{
-	Reload: constants.Keymap.Reload,
-	Quit:   constants.Keymap.Quit,
+func newScoreboardKeymap() scoreboardKeymap {
+	return scoreboardKeymap{
+		LineUp:     constants.TableKeyMap.LineUp,
+		LineDown:   constants.TableKeyMap.LineDown,
+		Reload:     constants.Keymap.Reload,
+		Challenges: constants.ScreensKeymap.Challenges,
+		Help:       constants.Keymap.Help,
+		Quit:       constants.Keymap.Quit,
+	}
 }
 
+var ScoreboardKeymap = newScoreboardKeymap()
+
 type scoreboardUpdatedMsg struct {
 	scoreboard []api.ScoreboardEntry
+	stale      cache.Status
 }
 
 type scoreboardModel struct {
-	scoreboard  table.Model
-	help        help.Model
-	screensHelp help.Model
-	err         error
-	width       int
-	height      int
+	scoreboard table.Model
+	reqs       *requests
+	help       help.Model
+	err        error
+	stale      cache.Status
+	width      int
+	height     int
 }
 
-func fetchScoreboardCmd() tea.Cmd {
-	return func() tea.Msg {
-		ctx, cancel := context.WithTimeout(context.Background(), constants.Timeout)
+func fetchScoreboardCmd(r *requests) tea.Cmd {
+	return r.sessionCmd(func(ctx context.Context) tea.Msg {
+		ctx, cancel := context.WithTimeout(ctx, constants.Timeout)
 		defer cancel()
+		ctx, status := cache.WithStatus(ctx)
 		log.Default().Println("Fetching scoreboard...")
 		scoreboard, err := constants.C.GetScoreboard(ctx)
 		if err != nil {
-			return createErrMsg(fmt.Errorf("Failed to fetch scoreboard: %v", err))
+			return createErrMsg(fmt.Errorf("Failed to fetch scoreboard: %w", err))
 		}
 		log.Default().Println("Fetched scoreboard")
-		return scoreboardUpdatedMsg{scoreboard}
-	}
+		return scoreboardUpdatedMsg{scoreboard, *status}
+	})
 }
 
 func setScoreboardTableSize(t *table.Model, width, height int) {
@@ -75,7 +89,7 @@ func setScoreboardTableSize(t *table.Model, width, height int) {
 		t.SetColumns(columns)
 
 		top, right, bottom, left := constants.DocStyle.GetMargin()
-		t.SetHeight(height - top - bottom - 5)
+		t.SetHeight(height - top - bottom - 6)
 		t.SetWidth(width - left - right + 1)
 	}
 }
@@ -93,6 +107,7 @@ func createScoreboardRows(scoreboard []api.ScoreboardEntry) []table.Row {
 func InitScoreboard(width, height int) (scoreboardModel, tea.Cmd) {
 	t := table.New(
 		table.WithFocused(true),
+		table.WithKeyMap(constants.TableKeyMap),
 	)
 
 	s := constants.TableStyle
@@ -100,57 +115,96 @@ func InitScoreboard(width, height int) (scoreboardModel, tea.Cmd) {
 	s.Selected = constants.SelectedRowStyle
 	t.SetStyles(s)
 	setScoreboardTableSize(&t, width, height)
-
-	return scoreboardModel{
-		scoreboard:  t,
-		help:        help.New(),
-		screensHelp: help.New(),
-		err:         nil,
-		width:       width,
-		height:      height,
-	}, fetchScoreboardCmd()
+	t.SetRows(createScoreboardRows(shared.getScoreboard()))
+
+	m := scoreboardModel{
+		scoreboard: t,
+		reqs:       newRequests(),
+		help:       help.New(),
+		err:        nil,
+		width:      width,
+		height:     height,
+	}
+	return m, fetchScoreboardCmd(m.reqs)
 }
 
 func (m scoreboardModel) Init() tea.Cmd { return nil }
 
+func (m scoreboardModel) requests() *requests { return m.reqs }
+
 func (m scoreboardModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	log.Default().Printf("Scoreboard view received message: %v, %T\n", msg, msg)
+	msg, cmd := m.reqs.accept(msg)
+	if msg == nil {
+		return m, cmd
+	}
+
 	switch msg := msg.(type) {
+	case reloadMsg:
+		return m, fetchScoreboardCmd(m.reqs)
 	case scoreboardUpdatedMsg:
+		shared.setScoreboard(msg.scoreboard)
 		m.scoreboard.SetRows(createScoreboardRows(msg.scoreboard))
+		m.stale = msg.stale
+		m.reqs.loaded(msg.stale)
 		return m, nil
 	case tea.WindowSizeMsg:
 		m.width = msg.Width
 		m.height = msg.Height
 		setScoreboardTableSize(&m.scoreboard, m.width, m.height)
 		return m, nil
+	case selectTeamMsg:
+		for i, row := range m.scoreboard.Rows() {
+			if row[1] == msg.name {
+				m.scoreboard.SetCursor(i)
+				break
+			}
+		}
+		return m, nil
 	case tea.KeyMsg:
-		switch {
-		case key.Matches(msg, constants.Keymap.Reload):
-			return m, fetchScoreboardCmd()
-		case key.Matches(msg, constants.Keymap.Quit):
-			return m, tea.Quit
-		case key.Matches(msg, constants.ScreensKeymap.Challenges):
-			cm, initCmd := InitChallenges(m.width, m.height)
-			return cm, initCmd
+		if action, ok := keyAction(msg, m.actions()...); ok {
+			return m, m.runAction(action)
 		}
+	case actionMsg:
+		return m, m.runAction(string(msg))
+	case tea.MouseMsg:
+		clickTable(&m.scoreboard, msg, statusBarHeight+1, 1)
+		return m, nil
+	case sessionExpiredMsg:
+		return InitLoginReturning(m, msg.retry, m.width, m.height)
 	case errMsg:
 		log.Default().Print(msg)
 		m.err = msg
 	}
 
-	var cmd tea.Cmd
 	m.scoreboard, cmd = m.scoreboard.Update(msg)
 
 	return m, cmd
 }
 
+func (m scoreboardModel) keyContext() string { return "Scoreboard" }
+
+func (m scoreboardModel) actions() []string {
+	return []string{constants.ActionReload, constants.ActionQuit, constants.ActionChallenges}
+}
+
+func (m scoreboardModel) runAction(action string) tea.Cmd {
+	switch action {
+	case constants.ActionReload:
+		return fetchScoreboardCmd(m.reqs)
+	case constants.ActionQuit:
+		return tea.Quit
+	case constants.ActionChallenges:
+		return switchTab(challengesTab)
+	}
+	return nil
+}
+
 func (m scoreboardModel) View() string {
-	screensHelpText := lipgloss.JoinHorizontal(lipgloss.Top, constants.HelpStyle(m.screensHelp.View(constants.ScreensKeymap)))
-	helpText := lipgloss.JoinHorizontal(lipgloss.Top, constants.HelpStyle(m.scoreboard.HelpView()), constants.HelpStyle(" • "), constants.HelpStyle(m.help.View(ScoreboardKeymap)))
+	helpText := m.help.View(ScoreboardKeymap)
 
 	if m.err != nil {
-		return lipgloss.JoinVertical(lipgloss.Top, constants.BaseStyle.Render(m.scoreboard.View()), screensHelpText, helpText, constants.ErrStyle(m.err.Error()))
+		return statusBarView(m.width, lipgloss.JoinVertical(lipgloss.Top, constants.BaseStyle.Render(m.scoreboard.View()), helpText, m.reqs.view(), renderStale(m.stale), renderError(m.err)))
 	}
-	return lipgloss.JoinVertical(lipgloss.Top, constants.BaseStyle.Render(m.scoreboard.View()), screensHelpText, helpText)
+	return statusBarView(m.width, lipgloss.JoinVertical(lipgloss.Top, constants.BaseStyle.Render(m.scoreboard.View()), helpText, m.reqs.view(), renderStale(m.stale)))
 }
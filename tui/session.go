@@ -0,0 +1,110 @@
+package tui
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sync"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/jonsth131/ctfd-cli/api"
+	"github.com/jonsth131/ctfd-cli/config"
+	"github.com/jonsth131/ctfd-cli/tui/constants"
+)
+
+type credentials struct {
+	user     string
+	password string
+}
+
+var (
+	credentialsMu sync.Mutex
+	savedCreds    *credentials
+)
+
+func setCredentials(user, password string) {
+	credentialsMu.Lock()
+	defer credentialsMu.Unlock()
+	savedCreds = &credentials{user, password}
+}
+
+func savedCredentials() *credentials {
+	credentialsMu.Lock()
+	defer credentialsMu.Unlock()
+	return savedCreds
+}
+
+// saveProfile stores changes to the selected profile in the config file.
+func saveProfile(profile config.Profile) error {
+	constants.Profile = profile
+	if constants.Config.Profiles == nil {
+		constants.Config.Profiles = map[string]config.Profile{}
+	}
+	constants.Config.Profiles[constants.ProfileName] = profile
+	return constants.Config.Save(constants.ConfigPath)
+}
+
+// switchToToken creates an API token after a password login when the profile
+// asks for it, and saves it to the profile instead of the password.
+func switchToToken(ctx context.Context) {
+	if !constants.Profile.AutoToken || constants.Profile.Token != "" {
+		return
+	}
+
+	log.Default().Print("Creating API token...")
+	token, err := constants.C.CreateToken(ctx, "ctfd-cli", time.Time{})
+	if err != nil {
+		log.Default().Printf("Failed to create token: %v", err)
+		return
+	}
+
+	profile := constants.Profile
+	profile.Token = token.Value
+	profile.Password = ""
+	profile.Session = ""
+	if err := saveProfile(profile); err != nil {
+		log.Default().Printf("Failed to save token: %v", err)
+	}
+}
+
+// sessionExpiredMsg is sent when the session expired and couldn't be renewed
+// automatically. retry is the command that failed.
+type sessionExpiredMsg struct {
+	retry tea.Cmd
+}
+
+func isSessionExpired(msg tea.Msg) bool {
+	err, ok := msg.(error)
+	return ok && errors.Is(err, api.ErrSessionExpired)
+}
+
+// withSession runs cmd and, if the session has expired, logs in again with
+// the saved credentials and retries it once.
+func withSession(cmd tea.Cmd) tea.Cmd {
+	return func() tea.Msg {
+		msg := cmd()
+		if !isSessionExpired(msg) {
+			return msg
+		}
+
+		creds := savedCredentials()
+		if creds == nil {
+			return sessionExpiredMsg{cmd}
+		}
+
+		ctx, cancel := context.WithTimeout(appCtx, constants.Timeout)
+		defer cancel()
+		log.Default().Print("Session expired, logging in again...")
+		if err := constants.C.Login(ctx, creds.user, creds.password); err != nil {
+			log.Default().Printf("Failed to login again: %v", err)
+			return sessionExpiredMsg{cmd}
+		}
+
+		msg = cmd()
+		if isSessionExpired(msg) {
+			return sessionExpiredMsg{cmd}
+		}
+		return msg
+	}
+}
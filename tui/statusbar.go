@@ -0,0 +1,209 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/jonsth131/ctfd-cli/api"
+	"github.com/jonsth131/ctfd-cli/hooks"
+	"github.com/jonsth131/ctfd-cli/tui/constants"
+)
+
+// statusRefreshInterval is how often the data behind the status bar is
+// fetched again.
+const statusRefreshInterval = time.Minute
+
+// statusTickMsg redraws the current screen so the countdown in the status
+// bar moves.
+type statusTickMsg struct{}
+
+type statusInfo struct {
+	ctfName string
+	user    string
+	team    string
+	score   int32
+	place   string
+	solved  int
+	total   int
+	end     time.Time
+	loaded  bool
+}
+
+var (
+	statusMu sync.Mutex
+	status   statusInfo
+	// watcher finds the events for the hooks in what refreshStatus fetches.
+	watcher hooks.Watcher
+	// refreshMu serializes refreshStatus, so the watcher sees the snapshots
+	// in the order they were fetched and an older one can't undo a newer one.
+	refreshMu sync.Mutex
+)
+
+func currentStatus() statusInfo {
+	statusMu.Lock()
+	defer statusMu.Unlock()
+	return status
+}
+
+// runStatusBar refreshes the status bar in the background until ctx is done.
+// Screens pick up the new data on the next redraw, which the ticks trigger.
+func runStatusBar(ctx context.Context, p *tea.Program) {
+	refresh := time.NewTicker(statusRefreshInterval)
+	defer refresh.Stop()
+	tick := time.NewTicker(time.Second)
+	defer tick.Stop()
+
+	refreshStatus(ctx)
+	p.Send(statusTickMsg{})
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-refresh.C:
+			refreshStatus(ctx)
+			p.Send(statusTickMsg{})
+		case <-tick.C:
+			if !currentStatus().end.IsZero() {
+				p.Send(statusTickMsg{})
+			}
+		}
+	}
+}
+
+// refreshStatus fetches everything shown in the status bar and runs the
+// hooks on what changed since the last time. Parts that fail, e.g. before
+// logging in, keep their previous value.
+func refreshStatus(ctx context.Context) {
+	refreshMu.Lock()
+	defer refreshMu.Unlock()
+
+	ctx, cancel := context.WithTimeout(ctx, constants.Timeout)
+	defer cancel()
+
+	next := currentStatus()
+
+	if ctf, err := constants.C.GetCTFStatus(ctx); err == nil {
+		next.ctfName = ctf.Name
+		next.end = ctf.End
+	}
+
+	user, err := constants.C.GetMe(ctx)
+	if err != nil {
+		log.Default().Printf("Status bar: %v", err)
+		return
+	}
+	next.user = user.Name
+	next.score = user.Score
+	next.place = user.Place
+
+	// In team mode the team's score and place are what counts.
+	if user.TeamId != 0 {
+		if team, err := constants.C.GetMyTeam(ctx); err == nil {
+			next.team = team.Name
+			next.score = team.Score
+			next.place = team.Place
+		}
+	}
+
+	snapshot := hooks.Snapshot{User: next.user, Team: next.team, Score: next.score, Rank: next.place}
+
+	if challenges, err := constants.C.GetChallenges(ctx); err == nil {
+		snapshot.Challenges = challenges
+		next.total = len(challenges)
+		next.solved = 0
+		for _, c := range challenges {
+			if c.SolvedByMe {
+				next.solved++
+			}
+		}
+	}
+
+	next.loaded = true
+
+	statusMu.Lock()
+	status = next
+	statusMu.Unlock()
+
+	if constants.Hooks.Wants(hooks.Notification) {
+		if notifications, err := constants.C.GetNotifications(ctx); err == nil {
+			snapshot.Notifications = notifications
+		} else {
+			log.Default().Printf("Hooks: %v", err)
+		}
+	}
+	for _, e := range watcher.Observe(snapshot) {
+		constants.Hooks.Run(e)
+	}
+}
+
+// refreshStatusCmd refreshes the status bar right away, e.g. after logging
+// in.
+func refreshStatusCmd() tea.Cmd {
+	return func() tea.Msg {
+		refreshStatus(appCtx)
+		return statusTickMsg{}
+	}
+}
+
+// updateSolved counts a challenge solved from the TUI right away instead of
+// waiting for the next refresh, and refreshes in the background so the new
+// score shows and the solve hooks run.
+func updateSolved(result *api.AttemptResult) {
+	if result == nil || result.Status != "correct" {
+		return
+	}
+	statusMu.Lock()
+	if status.solved < status.total {
+		status.solved++
+	}
+	statusMu.Unlock()
+	go refreshStatus(appCtx)
+}
+
+func renderStatusBar(width int) string {
+	s := currentStatus()
+
+	name := s.ctfName
+	if name == "" {
+		name = "ctfd-cli"
+	}
+
+	var parts []string
+	if s.loaded {
+		user := s.user
+		if s.team != "" {
+			user = fmt.Sprintf("%s (%s)", s.user, s.team)
+		}
+		parts = append(parts, user, fmt.Sprintf("%d pts", s.score))
+		if s.place != "" {
+			parts = append(parts, s.place)
+		}
+		parts = append(parts, fmt.Sprintf("%d/%d solved", s.solved, s.total))
+	}
+
+	if !s.end.IsZero() {
+		left := time.Until(s.end)
+		if left > 0 {
+			parts = append(parts, "ends in "+formatCountdown(left))
+		} else {
+			parts = append(parts, "ended")
+		}
+	}
+
+	line := constants.StatusBarAccentStyle.Render(" "+name+" ") + constants.StatusBarStyle.Render(" "+strings.Join(parts, " │ "))
+	return constants.StatusBarStyle.Width(width).MaxWidth(width).Render(line)
+}
+
+// statusBarHeight is the number of lines statusBarView puts above a screen.
+const statusBarHeight = 1
+
+// statusBarView puts the status bar above a screen.
+func statusBarView(width int, view string) string {
+	return lipgloss.JoinVertical(lipgloss.Left, renderStatusBar(width), view)
+}
@@ -0,0 +1,68 @@
+package tui
+
+import (
+	"sync"
+
+	"github.com/jonsth131/ctfd-cli/api"
+	"github.com/jonsth131/ctfd-cli/claims"
+)
+
+// store keeps the data one screen fetched for the others, so a newly opened
+// screen can show it right away while it refreshes.
+type store struct {
+	mu         sync.Mutex
+	challenges []api.ListChallenge
+	challenge  map[int]*api.Challenge
+	scoreboard []api.ScoreboardEntry
+	claims     claims.Board
+}
+
+var shared = &store{challenge: map[int]*api.Challenge{}}
+
+func (s *store) setChallenges(challenges []api.ListChallenge) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.challenges = challenges
+}
+
+func (s *store) getChallenges() []api.ListChallenge {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.challenges
+}
+
+func (s *store) setChallenge(challenge *api.Challenge) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.challenge[int(challenge.Id)] = challenge
+}
+
+func (s *store) getChallenge(id int) *api.Challenge {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.challenge[id]
+}
+
+func (s *store) setScoreboard(scoreboard []api.ScoreboardEntry) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.scoreboard = scoreboard
+}
+
+func (s *store) getScoreboard() []api.ScoreboardEntry {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.scoreboard
+}
+
+func (s *store) setClaims(board claims.Board) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.claims = board
+}
+
+func (s *store) getClaims() claims.Board {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.claims
+}
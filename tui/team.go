@@ -0,0 +1,183 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/spinner"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/jonsth131/ctfd-cli/tui/constants"
+)
+
+type teamJoinedMsg struct{}
+
+type teamMode int
+
+const (
+	joinTeam teamMode = iota
+	createTeam
+)
+
+// teamModel is shown after logging in when the CTF is played in teams and
+// the user has none yet.
+type teamModel struct {
+	mode       teamMode
+	focusIndex int
+	inputs     []textinput.Model
+	spinner    spinner.Model
+	reqs       *requests
+	loading    bool
+	err        error
+	width      int
+	height     int
+	next       tea.Model
+	nextCmd    tea.Cmd
+}
+
+func InitTeam(next tea.Model, cmd tea.Cmd, width, height int) (tea.Model, tea.Cmd) {
+	m := teamModel{
+		inputs:  newTeamInputs(),
+		reqs:    newRequests(),
+		width:   width,
+		height:  height,
+		next:    next,
+		nextCmd: cmd,
+	}
+
+	m.spinner = spinner.New()
+	m.spinner.Style = constants.SpinnerStyle
+	m.spinner.Spinner = spinner.Dot
+
+	return m, tea.Batch(focusInputs(m.inputs, 0), m.Init())
+}
+
+func newTeamInputs() []textinput.Model {
+	return []textinput.Model{
+		newFormInput("Team name"),
+		passwordInput(newFormInput("Team password")),
+	}
+}
+
+func teamCmd(r *requests, mode teamMode, name, password string) tea.Cmd {
+	return r.cmd(func(ctx context.Context) tea.Msg {
+		ctx, cancel := context.WithTimeout(ctx, constants.Timeout)
+		defer cancel()
+
+		var err error
+		if mode == createTeam {
+			log.Default().Printf("Creating team %s...", name)
+			err = constants.C.CreateTeam(ctx, name, password)
+		} else {
+			log.Default().Printf("Joining team %s...", name)
+			err = constants.C.JoinTeam(ctx, name, password)
+		}
+		if err != nil {
+			return createErrMsg(err)
+		}
+		return teamJoinedMsg{}
+	})
+}
+
+func (m teamModel) Init() tea.Cmd {
+	return tea.Batch(textinput.Blink, m.spinner.Tick)
+}
+
+func (m teamModel) requests() *requests { return m.reqs }
+
+func (m teamModel) typing() bool { return m.focusIndex < len(m.inputs) }
+
+func (m teamModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	msg, cmd := m.reqs.accept(msg)
+	if msg == nil {
+		return m, cmd
+	}
+
+	var cmds []tea.Cmd
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		return m, nil
+	case errMsg:
+		log.Default().Print(msg)
+		m.err = msg
+		m.loading = false
+		return m, nil
+	case teamJoinedMsg:
+		return continueTo(m.next, m.nextCmd, m.width, m.height)
+	case spinner.TickMsg:
+		var cmd tea.Cmd
+		m.spinner, cmd = m.spinner.Update(msg)
+		return m, cmd
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "ctrl+c", "esc":
+			return m, tea.Quit
+
+		case "ctrl+t":
+			if m.mode == joinTeam {
+				m.mode = createTeam
+			} else {
+				m.mode = joinTeam
+			}
+			m.err = nil
+			return m, nil
+
+		case "tab", "shift+tab", "enter", "up", "down":
+			if m.loading {
+				return m, nil
+			}
+
+			s := msg.String()
+			if s == "enter" && m.focusIndex == len(m.inputs) {
+				m.loading = true
+				m.err = nil
+				cmds = append(cmds, teamCmd(m.reqs, m.mode, m.inputs[0].Value(), m.inputs[1].Value()))
+			}
+
+			m.focusIndex = moveFocus(m.inputs, m.focusIndex, s)
+			cmds = append(cmds, focusInputs(m.inputs, m.focusIndex))
+		}
+	}
+
+	cmds = append(cmds, updateInputs(m.inputs, msg))
+
+	return m, tea.Batch(cmds...)
+}
+
+func (m teamModel) View() string {
+	if m.loading {
+		if m.mode == createTeam {
+			return fmt.Sprintf("\n %s%s", m.spinner.View(), "Creating team...")
+		}
+		return fmt.Sprintf("\n %s%s", m.spinner.View(), "Joining team...")
+	}
+
+	var b strings.Builder
+
+	if m.mode == createTeam {
+		b.WriteString("Create a team\n\n")
+	} else {
+		b.WriteString("Join a team\n\n")
+	}
+
+	for i := range m.inputs {
+		b.WriteString(m.inputs[i].View())
+		if i < len(m.inputs)-1 {
+			b.WriteRune('\n')
+		}
+	}
+
+	fmt.Fprintf(&b, "\n\n%s\n\n%s", submitButton(m.focusIndex == len(m.inputs)), renderError(m.err))
+
+	if m.mode == createTeam {
+		fmt.Fprintf(&b, "\n\n%s", constants.HelpStyle("ctrl+t join an existing team"))
+	} else {
+		fmt.Fprintf(&b, "\n\n%s", constants.HelpStyle("ctrl+t create a new team"))
+	}
+
+	return b.String()
+}
@@ -1,6 +1,7 @@
 package tui
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"log"
@@ -8,11 +9,30 @@ import (
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/jonsth131/ctfd-cli/api"
+	"github.com/jonsth131/ctfd-cli/cache"
+	"github.com/jonsth131/ctfd-cli/claims"
+	"github.com/jonsth131/ctfd-cli/config"
+	"github.com/jonsth131/ctfd-cli/hooks"
+	"github.com/jonsth131/ctfd-cli/notes"
 	"github.com/jonsth131/ctfd-cli/tui/constants"
 )
 
-func StartTea(url string, logging bool) {
-	if logging {
+type Options struct {
+	BaseURL  string
+	Logging  bool
+	Offline  bool
+	CacheDir string
+	NotesDir string
+	Config   *config.Config
+	// ConfigPath and ProfileName are used to save changes to the profile.
+	ConfigPath  string
+	ProfileName string
+	Profile     config.Profile
+	ApiOptions  api.Options
+}
+
+func StartTea(opts Options) {
+	if opts.Logging {
 		if f, err := tea.LogToFile("debug.log", "ctfd-cli"); err != nil {
 			fmt.Println("Couldn't open a file for logging:", err)
 			os.Exit(1)
@@ -28,16 +48,75 @@ func StartTea(url string, logging bool) {
 		log.SetOutput(io.Discard)
 	}
 
-	client, err := api.NewApiClient(url)
+	client, err := api.NewApiClientWithOptions(opts.BaseURL, opts.ApiOptions)
 	if err != nil {
-		fmt.Println("Failed to create Api Client")
-		log.Fatal(err)
+		fmt.Println("Failed to create Api Client:", err)
+		os.Exit(1)
+	}
+
+	constants.C = cache.New(client, opts.CacheDir, opts.Offline)
+	constants.Timeout = opts.ApiOptions.Budget()
+	constants.Config = opts.Config
+	constants.Profile = opts.Profile
+	if constants.Profile.URL == "" {
+		constants.Profile.URL = opts.BaseURL
+	}
+	constants.ConfigPath = opts.ConfigPath
+	constants.ProfileName = opts.ProfileName
+	constants.Notes = notes.New(opts.NotesDir)
+	if c := opts.Profile.Claims; c.URL != "" {
+		constants.Claims = claims.NewClient(c.URL, c.Token)
+	}
+
+	if err := constants.ApplyTheme(opts.Config.Theme, opts.Config.Themes); err != nil {
+		fmt.Println("Invalid theme:", err)
+		os.Exit(1)
+	}
+	if err := loadKeymaps(); err != nil {
+		fmt.Println("Invalid key bindings:", err)
+		os.Exit(1)
+	}
+	if constants.Hooks, err = hooks.NewRunner(opts.Config.Hooks); err != nil {
+		fmt.Println("Invalid hooks:", err)
+		os.Exit(1)
 	}
 
-	constants.C = client
+	loggedIn := opts.Offline
+	if opts.Profile.Token != "" {
+		client.SetToken(opts.Profile.Token)
+		loggedIn = true
+	} else if opts.Profile.Session != "" && !opts.Offline {
+		cookies, err := api.ParseCookies(opts.Profile.Session)
+		if err == nil {
+			err = client.ImportSession(cookies, opts.Profile.Network.UserAgent)
+		}
+		if err != nil {
+			fmt.Println("Failed to import saved session:", err)
+			os.Exit(1)
+		}
+		loggedIn = true
+	}
+
+	// Every request of the screens is made with a context derived from this
+	// one, so quitting aborts them.
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	appCtx = ctx
+
+	var m tea.Model
+	var cmd tea.Cmd
+	if loggedIn {
+		m, cmd = InitChallenges(0, 0)
+	} else {
+		m, cmd = InitLogin()
+	}
+	constants.P = tea.NewProgram(newRootModel(m, cmd), tea.WithAltScreen(), tea.WithMouseCellMotion())
+
+	go runStatusBar(ctx, constants.P)
+	if constants.Claims != nil {
+		go runClaims(ctx, constants.P)
+	}
 
-	m, _ := InitLogin()
-	constants.P = tea.NewProgram(m, tea.WithAltScreen())
 	if _, err := constants.P.Run(); err != nil {
 		log.Fatal(err)
 	}
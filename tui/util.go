@@ -3,7 +3,13 @@ package tui
 import (
 	"context"
 	"errors"
+	"fmt"
+	"strings"
+	"unicode"
 
+	"github.com/charmbracelet/lipgloss"
+	"github.com/jonsth131/ctfd-cli/api"
+	"github.com/jonsth131/ctfd-cli/cache"
 	"github.com/jonsth131/ctfd-cli/tui/constants"
 )
 
@@ -13,6 +19,8 @@ type (
 
 func (e errMsg) Error() string { return e.err.Error() }
 
+func (e errMsg) Unwrap() error { return e.err }
+
 func createErrMsg(err error) error {
 	if errors.Is(err, context.DeadlineExceeded) {
 		return errMsg{errors.New("Request timed out")}
@@ -27,5 +35,48 @@ func renderError(err error) string {
 	if err == nil {
 		return ""
 	}
+
+	// Show the start of unexpected responses, such as proxy error pages,
+	// below the error itself.
+	var apiErr *api.APIError
+	if errors.As(err, &apiErr) && apiErr.Snippet != "" {
+		return lipgloss.JoinVertical(lipgloss.Left, constants.ErrStyle(err.Error()), constants.HelpStyle(apiErr.Snippet))
+	}
+
 	return constants.ErrStyle(err.Error())
 }
+
+func renderStale(status cache.Status) string {
+	if !status.Stale {
+		return ""
+	}
+	if errors.Is(status.Err, cache.ErrOffline) {
+		return constants.AlertStyle(fmt.Sprintf("Offline - showing data from %s", status.Since.Format("2006-01-02 15:04:05")))
+	}
+	return constants.AlertStyle(fmt.Sprintf("Stale since %s - %v", status.Since.Format("2006-01-02 15:04:05"), status.Err))
+}
+
+// capitalize upper-cases the first letter of s.
+func capitalize(s string) string {
+	r := []rune(s)
+	if len(r) == 0 {
+		return s
+	}
+	return string(unicode.ToUpper(r[0])) + string(r[1:])
+}
+
+// safeName turns a challenge name into something usable as a directory name.
+func safeName(name string) string {
+	name = strings.Map(func(r rune) rune {
+		switch r {
+		case '/', '\\', ':', '*', '?', '"', '<', '>', '|':
+			return '_'
+		}
+		return r
+	}, strings.TrimSpace(name))
+
+	if name == "" || name == "." || name == ".." {
+		return "_"
+	}
+	return name
+}
@@ -0,0 +1,340 @@
+package tui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/jonsth131/ctfd-cli/api"
+	"github.com/jonsth131/ctfd-cli/tui/constants"
+)
+
+// grabWorkers is how many challenges are fetched at once when the CTF starts.
+const grabWorkers = 8
+
+type (
+	ctfStatusMsg struct {
+		status *api.CTFStatus
+	}
+	// pollCTFMsg carries the generation of the poll it belongs to, so a
+	// manual refresh doesn't start a second polling loop.
+	pollCTFMsg struct {
+		gen int
+	}
+	countdownTickMsg time.Time
+	grabbedMsg       struct {
+		challenges int
+		files      int
+		errs       []error
+	}
+)
+
+// waitModel counts down to the start of the CTF and grabs every challenge
+// with its files as soon as they are released.
+type waitModel struct {
+	status   *api.CTFStatus
+	reqs     *requests
+	gen      int
+	now      time.Time
+	checked  time.Time
+	nextPoll time.Duration
+	grabbing bool
+	grabbed  *grabbedMsg
+	err      error
+	width    int
+	height   int
+}
+
+func InitWait(status *api.CTFStatus, width, height int) (tea.Model, tea.Cmd) {
+	m := waitModel{
+		status:  status,
+		reqs:    newRequests(),
+		now:     time.Now(),
+		checked: time.Now(),
+		width:   width,
+		height:  height,
+	}
+
+	m.nextPoll = pollInterval(status, m.now)
+	return m, tea.Batch(countdownTick(), schedulePoll(m.nextPoll, m.gen))
+}
+
+func fetchCTFStatusCmd(r *requests) tea.Cmd {
+	return r.sessionCmd(func(ctx context.Context) tea.Msg {
+		ctx, cancel := context.WithTimeout(ctx, constants.Timeout)
+		defer cancel()
+		log.Default().Print("Checking CTF status...")
+		status, err := constants.C.GetCTFStatus(ctx)
+		if err != nil {
+			return createErrMsg(err)
+		}
+		return ctfStatusMsg{status}
+	})
+}
+
+func schedulePoll(d time.Duration, gen int) tea.Cmd {
+	return tea.Tick(d, func(time.Time) tea.Msg { return pollCTFMsg{gen} })
+}
+
+func countdownTick() tea.Cmd {
+	return tea.Tick(time.Second, func(t time.Time) tea.Msg { return countdownTickMsg(t) })
+}
+
+// pollInterval checks rarely while the start is far away and often close to
+// it, with one check right at the start time.
+func pollInterval(status *api.CTFStatus, now time.Time) time.Duration {
+	switch status.State {
+	case api.CTFEnded:
+		return 5 * time.Minute
+	case api.CTFPaused:
+		return 30 * time.Second
+	}
+
+	if status.Start.IsZero() {
+		return time.Minute
+	}
+
+	remaining := status.Start.Sub(now)
+	var d time.Duration
+	switch {
+	case remaining <= 0:
+		// The clocks disagree or the admins start the CTF by hand.
+		return 2 * time.Second
+	case remaining > time.Hour:
+		d = 5 * time.Minute
+	case remaining > 10*time.Minute:
+		d = time.Minute
+	case remaining > time.Minute:
+		d = 15 * time.Second
+	default:
+		d = 2 * time.Second
+	}
+
+	if remaining < d {
+		d = remaining
+	}
+	return d
+}
+
+// isForbidden reports whether the API refused a request, which is what the
+// challenges API does while the CTF isn't running.
+func isForbidden(err error) bool {
+	var apiErr *api.APIError
+	return errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusForbidden
+}
+
+// grabChallengesCmd fetches every challenge and downloads its files, a few
+// challenges at a time.
+func grabChallengesCmd(r *requests) tea.Cmd {
+	return r.progressCmd(func(ctx context.Context, progress func(done, total int)) tea.Msg {
+		listCtx, cancel := context.WithTimeout(ctx, constants.Timeout)
+		challenges, err := constants.C.GetChallenges(listCtx)
+		cancel()
+		if err != nil {
+			return createErrMsg(fmt.Errorf("Failed to fetch challenges: %w", err))
+		}
+
+		var (
+			mu   sync.Mutex
+			wg   sync.WaitGroup
+			done int
+			msg  = grabbedMsg{challenges: len(challenges)}
+		)
+		sem := make(chan struct{}, grabWorkers)
+
+		for _, c := range challenges {
+			wg.Add(1)
+			go func(id uint32) {
+				defer wg.Done()
+				sem <- struct{}{}
+				defer func() { <-sem }()
+
+				files, err := grabChallenge(ctx, int(id))
+
+				mu.Lock()
+				defer mu.Unlock()
+				done++
+				progress(done, len(challenges))
+				msg.files += files
+				if err != nil {
+					msg.errs = append(msg.errs, err)
+				}
+			}(c.Id)
+		}
+
+		wg.Wait()
+		log.Default().Printf("Grabbed %d challenges and %d files", msg.challenges, msg.files)
+		return msg
+	})
+}
+
+func grabChallenge(ctx context.Context, id int) (int, error) {
+	fetchCtx, cancel := context.WithTimeout(ctx, constants.Timeout)
+	challenge, err := constants.C.GetChallenge(fetchCtx, uint16(id))
+	cancel()
+	if err != nil {
+		return 0, fmt.Errorf("Failed to fetch challenge %d: %w", id, err)
+	}
+
+	// Like downloadFilesCmd, the files are downloaded without a deadline.
+	if _, err := saveChallengeFiles(ctx, *challenge, nil); err != nil {
+		return 0, fmt.Errorf("%s: %w", challenge.Name, err)
+	}
+	return len(challenge.Files), nil
+}
+
+func (m waitModel) Init() tea.Cmd {
+	return tea.Batch(countdownTick(), schedulePoll(m.nextPoll, m.gen))
+}
+
+func (m waitModel) requests() *requests { return m.reqs }
+
+func (m waitModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	msg, cmd := m.reqs.accept(msg)
+	if msg == nil {
+		return m, cmd
+	}
+
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		return m, nil
+	case countdownTickMsg:
+		m.now = time.Time(msg)
+		return m, countdownTick()
+	case pollCTFMsg:
+		if msg.gen != m.gen || m.grabbing {
+			return m, nil
+		}
+		return m, fetchCTFStatusCmd(m.reqs)
+	case ctfStatusMsg:
+		m.status = msg.status
+		m.checked = time.Now()
+		m.err = nil
+		if m.status.State == api.CTFRunning {
+			log.Default().Print("CTF started, grabbing challenges")
+			m.grabbing = true
+			return m, grabChallengesCmd(m.reqs)
+		}
+		m.nextPoll = pollInterval(m.status, m.checked)
+		return m, schedulePoll(m.nextPoll, m.gen)
+	case grabbedMsg:
+		m.grabbing = false
+		if len(msg.errs) == 0 {
+			return InitChallenges(m.width, m.height)
+		}
+		m.grabbed = &msg
+		return m, nil
+	case sessionExpiredMsg:
+		return InitLoginReturning(m, msg.retry, m.width, m.height)
+	case errMsg:
+		log.Default().Print(msg)
+		m.err = msg
+		// The challenges are often not available the moment the CTF
+		// starts, so a failed grab is retried like a failed check.
+		m.grabbing = false
+		m.checked = time.Now()
+		m.nextPoll = 5 * time.Second
+		return m, schedulePoll(m.nextPoll, m.gen)
+	case tea.KeyMsg:
+		switch {
+		case key.Matches(msg, constants.Keymap.Quit):
+			return m, tea.Quit
+		case key.Matches(msg, constants.Keymap.Reload):
+			if m.grabbing {
+				return m, nil
+			}
+			m.gen++
+			m.reqs.cancel()
+			m.err = nil
+			return m, fetchCTFStatusCmd(m.reqs)
+		case key.Matches(msg, constants.Keymap.Enter):
+			if !m.grabbing {
+				return InitChallenges(m.width, m.height)
+			}
+		}
+	}
+	return m, nil
+}
+
+func formatCountdown(d time.Duration) string {
+	if d < 0 {
+		d = 0
+	}
+	d = d.Round(time.Second)
+	days := d / (24 * time.Hour)
+	d -= days * 24 * time.Hour
+	s := fmt.Sprintf("%02d:%02d:%02d", int(d.Hours()), int(d.Minutes())%60, int(d.Seconds())%60)
+	if days > 0 {
+		return fmt.Sprintf("%dd %s", days, s)
+	}
+	return s
+}
+
+func (m waitModel) View() string {
+	return statusBarView(m.width, m.body())
+}
+
+func (m waitModel) body() string {
+	var b strings.Builder
+
+	if m.grabbing {
+		fmt.Fprintf(&b, "\n The CTF has started! Fetching challenges and downloading files...\n\n %s", m.reqs.view())
+		return b.String()
+	}
+
+	if m.grabbed != nil {
+		fmt.Fprintf(&b, "Fetched %d challenges and %d files, %d failed:\n\n", m.grabbed.challenges, m.grabbed.files, len(m.grabbed.errs))
+		for _, err := range m.grabbed.errs {
+			b.WriteString(renderError(err) + "\n")
+		}
+		fmt.Fprintf(&b, "\n%s", constants.HelpStyle(fmt.Sprintf("%s continue • %s quit", constants.Keymap.Enter.Help().Key, constants.Keymap.Quit.Help().Key)))
+		return b.String()
+	}
+
+	switch m.status.State {
+	case api.CTFEnded:
+		b.WriteString("The CTF has ended\n\n")
+		if !m.status.End.IsZero() {
+			fmt.Fprintf(&b, "Ended %s\n", m.status.End.Local().Format("Mon 2006-01-02 15:04 MST"))
+		}
+	case api.CTFPaused:
+		b.WriteString("The CTF is paused\n\n")
+	case api.CTFRunning:
+		b.WriteString("The CTF has started, but the challenges couldn't be fetched yet\n")
+	default:
+		b.WriteString("The CTF has not started yet\n\n")
+		if m.status.Start.IsZero() {
+			b.WriteString("The start time hasn't been announced\n")
+		} else {
+			countdown := constants.FocusedStyle.Render(formatCountdown(m.status.Start.Sub(m.now)))
+			fmt.Fprintf(&b, "Starts in %s (%s)\n", countdown, m.status.Start.Local().Format("Mon 2006-01-02 15:04 MST"))
+		}
+	}
+
+	for _, message := range m.status.Messages {
+		fmt.Fprintf(&b, "\n%s", constants.AlertStyle(message))
+	}
+
+	fmt.Fprintf(&b, "\n\n%s", constants.HelpStyle(fmt.Sprintf("Last checked %s, next check in %s", m.checked.Format("15:04:05"), m.nextPoll.Round(time.Second))))
+	if m.status.State == api.CTFNotStarted {
+		fmt.Fprintf(&b, "\n%s", constants.HelpStyle("Challenges and files are downloaded as soon as they are released"))
+	}
+
+	if m.err != nil {
+		fmt.Fprintf(&b, "\n\n%s", renderError(m.err))
+	}
+
+	fmt.Fprintf(&b, "\n\n%s", constants.HelpStyle(fmt.Sprintf("%s check now • %s open challenges • %s quit",
+		constants.Keymap.Reload.Help().Key, constants.Keymap.Enter.Help().Key, constants.Keymap.Quit.Help().Key)))
+
+	return b.String()
+}
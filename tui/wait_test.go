@@ -0,0 +1,121 @@
+package tui
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/jonsth131/ctfd-cli/api"
+	"github.com/jonsth131/ctfd-cli/config"
+	"github.com/jonsth131/ctfd-cli/tui/constants"
+)
+
+func TestPollInterval(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	startIn := func(d time.Duration) *api.CTFStatus {
+		return &api.CTFStatus{State: api.CTFNotStarted, Start: now.Add(d)}
+	}
+
+	tests := []struct {
+		name     string
+		status   *api.CTFStatus
+		expected time.Duration
+	}{
+		{"Ended", &api.CTFStatus{State: api.CTFEnded}, 5 * time.Minute},
+		{"Paused", &api.CTFStatus{State: api.CTFPaused}, 30 * time.Second},
+		{"No start time", &api.CTFStatus{State: api.CTFNotStarted}, time.Minute},
+		{"Days away", startIn(48 * time.Hour), 5 * time.Minute},
+		{"Just over an hour", startIn(time.Hour + time.Second), 5 * time.Minute},
+		{"An hour", startIn(time.Hour), time.Minute},
+		{"Over ten minutes", startIn(10*time.Minute + time.Second), time.Minute},
+		{"Ten minutes", startIn(10 * time.Minute), 15 * time.Second},
+		{"A minute", startIn(time.Minute), 2 * time.Second},
+		{"Shorter than the interval", startIn(time.Second), time.Second},
+		{"Start passed", startIn(-time.Minute), 2 * time.Second},
+	}
+
+	for _, test := range tests {
+		if d := pollInterval(test.status, now); d != test.expected {
+			t.Errorf("%s: pollInterval() = %v, want %v", test.name, d, test.expected)
+		}
+	}
+}
+
+func TestFormatCountdown(t *testing.T) {
+	tests := []struct {
+		input    time.Duration
+		expected string
+	}{
+		{-time.Minute, "00:00:00"},
+		{0, "00:00:00"},
+		{1500 * time.Millisecond, "00:00:02"},
+		{time.Hour + 2*time.Minute + 3*time.Second, "01:02:03"},
+		{23*time.Hour + 59*time.Minute + 59*time.Second, "23:59:59"},
+		{24 * time.Hour, "1d 00:00:00"},
+		{50*time.Hour + 30*time.Second, "2d 02:00:30"},
+	}
+
+	for _, test := range tests {
+		if s := formatCountdown(test.input); s != test.expected {
+			t.Errorf("formatCountdown(%v) = %q, want %q", test.input, s, test.expected)
+		}
+	}
+}
+
+func TestGrabChallengeSlowDownload(t *testing.T) {
+	const chunks = 5
+	timeout := 50 * time.Millisecond
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v1/challenges/1":
+			w.Header().Set("Content-Type", "application/json")
+			fmt.Fprint(w, `{"success": true, "data": {"id": 1, "name": "Slow", "files": ["/files/abc/slow.bin"]}}`)
+		case "/files/abc/slow.bin":
+			// The whole body takes several timeouts, but data keeps
+			// arriving.
+			for range chunks {
+				w.Write(bytes.Repeat([]byte("x"), 1024))
+				w.(http.Flusher).Flush()
+				time.Sleep(timeout / 2)
+			}
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	opts := api.DefaultOptions()
+	opts.Timeout = timeout
+	client, err := api.NewApiClientWithOptions(server.URL, opts)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	c, cfg, d := constants.C, constants.Config, constants.Timeout
+	defer func() { constants.C, constants.Config, constants.Timeout = c, cfg, d }()
+	constants.C = client
+	constants.Config = &config.Config{DownloadDir: t.TempDir()}
+	constants.Timeout = timeout
+
+	files, err := grabChallenge(context.Background(), 1)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if files != 1 {
+		t.Errorf("expected one file, got %d", files)
+	}
+	data, err := os.ReadFile(filepath.Join(constants.Config.DownloadDir, "Slow", "slow.bin"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(data) != chunks*1024 {
+		t.Errorf("expected %d bytes, got %d", chunks*1024, len(data))
+	}
+}